@@ -1,6 +1,64 @@
 package cli
 
-import "flag"
+import (
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//NegativeNumbersUsage documents in help output that negative numbers are
+//parameters when ParseOptions.NegativeNumbers is true.
+const NegativeNumbersUsage = "Arguments that are negative numbers, e.g. -5 or -3.2, are parameters unless a flag of that name is defined."
+
+//ParseOptions select how ParseArguments parses arguments.
+type ParseOptions struct {
+	//GNU parses args with the semantics of ParseArgumentsGNU.
+	GNU bool
+
+	//StopAtFirstParameter stops parsing flags at the first parameter as in
+	//ParseArgumentsNonInterspersed and ParseFlagsGNU.
+	StopAtFirstParameter bool
+
+	//Passthrough returns the arguments after "--" in passthrough instead of params
+	//as in ParseArgumentsPassthrough. It has no effect if StopAtFirstParameter
+	//is true.
+	Passthrough bool
+
+	//NegativeNumbers treats arguments that are negative numbers, e.g. -5 or -3.2,
+	//as parameters unless a flag of that name is defined. In GNU mode, the flag
+	//named by the first digit must not be defined.
+	NegativeNumbers bool
+}
+
+//ParseArguments parses the flags in args into f with the parser selected by opts
+//and returns the parameters. Passthrough is always nil unless opts.Passthrough is
+//true.
+func ParseArguments(f *flag.FlagSet, args []string, opts ParseOptions) (params, passthrough []string, err error) {
+	switch {
+	case opts.GNU && opts.StopAtFirstParameter:
+		params, passthrough, err = parseGNU(f, args, false, opts.NegativeNumbers)
+		if err != nil {
+			return nil, nil, err
+		}
+		return append(append([]string{}, params...), passthrough...), nil, nil
+	case opts.StopAtFirstParameter:
+		params, err = parseNonInterspersed(f, args, opts.NegativeNumbers)
+		return params, nil, err
+	case opts.GNU:
+		params, passthrough, err = parseGNU(f, args, true, opts.NegativeNumbers)
+	default:
+		params, passthrough, err = parseInterspersed(f, args, opts.NegativeNumbers)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !opts.Passthrough {
+		return append(params, passthrough...), nil, nil
+	}
+	return params, passthrough, nil
+}
 
 //ParseArgumentsInterspersed allows argument parsing to be more flexible than
 //what is provided natively in the flag package.
@@ -15,19 +73,44 @@ import "flag"
 //	[]string{"one" "-count" "22" "two" "-value" "foobar"}
 //Essentially, the order of the arguments do not matter when parsing. And non-flag
 //arguments are returned in params.
+//Flags that are OptionalValueFlags never take the following argument as their value.
 //Err will be any error returned from flag.FlagSet.Parse().
 func ParseArgumentsInterspersed(f *flag.FlagSet, args []string) (params []string, err error) {
+	params, _, err = ParseArguments(f, args, ParseOptions{})
+	return
+}
+
+//ParseArgumentsPassthrough parses args in the same way as ParseArgumentsInterspersed
+//except that the arguments after "--" are returned in passthrough instead of params.
+//Passthrough is nil if no "--" ends the flags.
+func ParseArgumentsPassthrough(f *flag.FlagSet, args []string) (params, passthrough []string, err error) {
+	return ParseArguments(f, args, ParseOptions{Passthrough: true})
+}
+
+//parseInterspersed parses args as in ParseArgumentsPassthrough. If numbers is
+//true, then negative numbers that do not name a flag are parameters.
+func parseInterspersed(f *flag.FlagSet, args []string, numbers bool) (params, passthrough []string, err error) {
 	params = []string{}
 	for err == nil && len(args) > 0 {
-		err = f.Parse(args)
+		args = ExpandOptionalValues(f, args)
+		n := len(args)
+		if numbers {
+			n = indexNegativeNumber(f, args)
+		}
+		err = f.Parse(args[:n])
 		if err != nil {
 			continue
 		}
-		if didStopAfterDoubleMinus(args, f.Args()) {
-			params = append(params, f.Args()...)
+		if didStopAfterDoubleMinus(args[:n], f.Args()) {
+			passthrough = append([]string{}, f.Args()...)
 			args = args[len(args):]
 			continue
 		}
+		if n < len(args) {
+			params = append(params, args[n])
+			args = args[n+1:]
+			continue
+		}
 		args = f.Args()
 		if len(args) > 0 {
 			params = append(params, args[0])
@@ -35,12 +118,186 @@ func ParseArgumentsInterspersed(f *flag.FlagSet, args []string) (params []string
 		}
 	}
 	if err != nil {
-		params = nil
-		return
+		return nil, nil, err
 	}
 	return
 }
 
+//PassthroughSetter is an optional interface that commands may implement in order
+//to receive the arguments after "--" separately from their parameters, e.g. to
+//forward them to a child process.
+//
+//Commands that stop at their first parameter, see ParameterStopper, receive all
+//arguments after their flags as parameters and a nil passthrough.
+type PassthroughSetter interface {
+	//SetPassthrough is called after SetParameters with the arguments after "--",
+	//or nil if there was no "--".
+	SetPassthrough(args []string) error
+}
+
+//ParseArgumentsNonInterspersed parses the flags in args until the first non-flag
+//argument is reached and returns that argument and all following arguments,
+//including any "--", in params. A "--" before the first non-flag argument ends
+//the flags and is not included in params.
+//
+//Err will be any error returned from flag.FlagSet.Parse().
+func ParseArgumentsNonInterspersed(f *flag.FlagSet, args []string) (params []string, err error) {
+	return parseNonInterspersed(f, args, false)
+}
+
+//parseNonInterspersed parses args as in ParseArgumentsNonInterspersed. If numbers
+//is true, then the first negative number that does not name a flag is the first
+//parameter.
+func parseNonInterspersed(f *flag.FlagSet, args []string, numbers bool) ([]string, error) {
+	args = ExpandOptionalValues(f, args)
+	n := len(args)
+	if numbers {
+		n = indexNegativeNumber(f, args)
+	}
+	if err := f.Parse(args[:n]); err != nil {
+		return nil, err
+	}
+	if n < len(args) {
+		return append([]string{}, args[n:]...), nil
+	}
+	return append([]string{}, f.Args()...), nil
+}
+
+//indexNegativeNumber returns the index of the first negative number in the flags
+//at the start of args that does not name a flag of f, or len(args) if there is
+//none. Flags are recognized in the same way as the flag package.
+func indexNegativeNumber(f *flag.FlagSet, args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == DoubleMinus {
+			break
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		fl := f.Lookup(name)
+		if fl == nil {
+			if isNegativeNumber(arg) {
+				return i
+			}
+			break
+		}
+		if !IsBoolFlag(fl) {
+			i++
+		}
+	}
+	return len(args)
+}
+
+//isNegativeNumber returns whether or not arg is a negative decimal number such as
+//-5, -3.2, -.5, or -1e3.
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' || !(arg[1] == '.' || '0' <= arg[1] && arg[1] <= '9') {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg[1:], 64)
+	return err == nil
+}
+
+//ParameterStopper is an optional interface that commands may implement in order
+//to have the parsing of their flags stop at their first parameter.
+//This is useful for commands that wrap other commands, e.g. "prog exec cmd -flag",
+//where the flags after the first parameter belong to the wrapped command.
+type ParameterStopper interface {
+	//StopAtFirstParameter returns whether or not flag parsing stops at the first
+	//parameter.
+	StopAtFirstParameter() bool
+}
+
+//StopsAtFirstParameter returns whether or not v is a ParameterStopper whose
+//StopAtFirstParameter method returns true.
+func StopsAtFirstParameter(v interface{}) bool {
+	ps, ok := v.(ParameterStopper)
+	return ok && ps.StopAtFirstParameter()
+}
+
 func didStopAfterDoubleMinus(args, remaining []string) bool {
 	return len(args) > len(remaining) && args[len(args)-len(remaining)-1] == DoubleMinus
 }
+
+//SplitArgs splits s into arguments following the quoting rules of a POSIX shell.
+//Arguments are separated by unquoted whitespace. Within single quotes, every
+//character is literal. Within double quotes, a backslash escapes only $, `, ",
+//\, and newline. Elsewhere, a backslash escapes any character.
+//No expansion of variables, globs, or the like is performed.
+//
+//Err is ErrUnterminatedQuote if a quote is not closed or s ends in an unquoted
+//backslash.
+//	SplitArgs(`-name "a b" -path 'c d' e\ f`) // ["-name" "a b" "-path" "c d" "e f"]
+func SplitArgs(s string) (args []string, err error) {
+	args = []string{}
+	arg := []rune{}
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("$`\"\\\n", r) {
+				arg = append(arg, '\\')
+			}
+			if r != '\n' {
+				arg = append(arg, r)
+			}
+			escaped = false
+		case quote == '\'':
+			if r == quote {
+				quote = 0
+			} else {
+				arg = append(arg, r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if r == quote {
+				quote = 0
+			} else {
+				arg = append(arg, r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, string(arg))
+				arg, inArg = arg[:0], false
+			}
+		default:
+			arg, inArg = append(arg, r), true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, ErrUnterminatedQuote
+	}
+	if inArg {
+		args = append(args, string(arg))
+	}
+	return args, nil
+}
+
+//EnvArgs returns the arguments in the environment variable named name split by
+//SplitArgs. It returns no arguments if the variable is not present.
+//If lookupEnv is nil, then os.LookupEnv is used.
+//
+//The returned error, if not nil, is an *EnvValueError.
+func EnvArgs(name string, lookupEnv func(string) (string, bool)) ([]string, error) {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	value, ok := lookupEnv(name)
+	if !ok {
+		return []string{}, nil
+	}
+	args, err := SplitArgs(value)
+	if err != nil {
+		return nil, &EnvValueError{Name: name, Value: value, Err: err}
+	}
+	return args, nil
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"flag"
 	"io/ioutil"
 	"reflect"
@@ -125,3 +126,158 @@ func newFlagSet(name string) *flag.FlagSet {
 	f.SetOutput(ioutil.Discard)
 	return f
 }
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		s    string
+		args []string
+		err  error
+	}{
+		{"", []string{}, nil},
+		{" \t\n", []string{}, nil},
+		{"-a  b\tc\n", []string{"-a", "b", "c"}, nil},
+		{`-name "a b" -path 'c d' e\ f`, []string{"-name", "a b", "-path", "c d", "e f"}, nil},
+		{`'' "" a""b`, []string{"", "", "ab"}, nil},
+		{`'a\b "c"'`, []string{`a\b "c"`}, nil},
+		{`"a\b \"c\" \\ \$"`, []string{`a\b "c" \ $`}, nil},
+		{"a\\\nb", []string{"ab"}, nil},
+		{`'a`, nil, ErrUnterminatedQuote},
+		{`"a`, nil, ErrUnterminatedQuote},
+		{`a\`, nil, ErrUnterminatedQuote},
+	}
+
+	for i, test := range tests {
+		args, err := SplitArgs(test.s)
+		if !reflect.DeepEqual(args, test.args) || err != test.err {
+			t.Errorf("%v: SplitArgs(%q) = %q, %v WANT %q, %v", i, test.s, args, err, test.args, test.err)
+		}
+	}
+}
+
+func TestEnvArgs(t *testing.T) {
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := map[string]string{"PROG_OPTS": "-a 'b c'", "BAD_OPTS": `"a`}[name]
+		return value, ok
+	}
+
+	args, err := EnvArgs("PROG_OPTS", lookupEnv)
+	if !reflect.DeepEqual(args, []string{"-a", "b c"}) || err != nil {
+		t.Errorf("EnvArgs(PROG_OPTS) = %q, %v", args, err)
+	}
+
+	args, err = EnvArgs("NONE_OPTS", lookupEnv)
+	if !reflect.DeepEqual(args, []string{}) || err != nil {
+		t.Errorf("EnvArgs(NONE_OPTS) = %q, %v", args, err)
+	}
+
+	_, err = EnvArgs("BAD_OPTS", lookupEnv)
+	want := &EnvValueError{Name: "BAD_OPTS", Value: `"a`, Err: ErrUnterminatedQuote}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("EnvArgs(BAD_OPTS) err = %v WANT %v", err, want)
+	}
+}
+
+func TestParseArgumentsNonInterspersed(t *testing.T) {
+	tests := []struct {
+		args   []string
+		params []string
+		err    error
+	}{
+		{[]string{}, []string{}, nil},
+		{[]string{"-bool", "-string", "s", "cmd", "-bool", "--", "x"}, []string{"cmd", "-bool", "--", "x"}, nil},
+		{[]string{"-bool", "--", "-string", "s"}, []string{"-string", "s"}, nil},
+		{[]string{"-other"}, nil, errors.New("flag provided but not defined: -other")},
+	}
+
+	for i, test := range tests {
+		f := newFlagSet("")
+		f.Bool("bool", false, "")
+		f.String("string", "", "")
+
+		params, err := ParseArgumentsNonInterspersed(f, test.args)
+		if !reflect.DeepEqual(params, test.params) || !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: params, err = %q, %v WANT %q, %v", i, params, err, test.params, test.err)
+		}
+	}
+}
+
+func TestParseArgumentsPassthrough(t *testing.T) {
+	tests := []struct {
+		args        []string
+		params      []string
+		passthrough []string
+		err         error
+	}{
+		{[]string{}, []string{}, nil, nil},
+		{[]string{"a", "-bool", "b"}, []string{"a", "b"}, nil, nil},
+		{[]string{"a", "-bool", "--", "-string", "s"}, []string{"a"}, []string{"-string", "s"}, nil},
+		{[]string{"-string", "s", "a", "--"}, []string{"a"}, []string{}, nil},
+		{[]string{"--"}, []string{}, []string{}, nil},
+		{[]string{"-other", "--", "a"}, nil, nil, errors.New("flag provided but not defined: -other")},
+	}
+
+	for i, test := range tests {
+		f := newFlagSet("")
+		f.Bool("bool", false, "")
+		f.String("string", "", "")
+
+		params, passthrough, err := ParseArgumentsPassthrough(f, test.args)
+		if !reflect.DeepEqual(params, test.params) || !reflect.DeepEqual(passthrough, test.passthrough) || !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: params, passthrough, err = %q, %q, %v WANT %q, %q, %v", i, params, passthrough, err, test.params, test.passthrough, test.err)
+		}
+	}
+}
+
+func TestParseArguments_NegativeNumbers(t *testing.T) {
+	tests := []struct {
+		opts   ParseOptions
+		args   string
+		params []string
+		err    error
+	}{
+		{ParseOptions{}, "-5", nil, errors.New("flag provided but not defined: -5")},
+		{ParseOptions{NegativeNumbers: true}, "-5 -bool -3.2 x -.5 -1e3", []string{"-5", "-3.2", "x", "-.5", "-1e3"}, nil},
+		{ParseOptions{NegativeNumbers: true}, "-int -5 -string -3 -6", []string{"-6"}, nil},
+		{ParseOptions{NegativeNumbers: true}, "-string=-7 -2 -- -8", []string{"-2", "-8"}, nil},
+		{ParseOptions{NegativeNumbers: true}, "-9 -8", nil, nil},
+		{ParseOptions{NegativeNumbers: true}, "-5x", nil, errors.New("flag provided but not defined: -5x")},
+		{ParseOptions{NegativeNumbers: true}, "-inf", nil, errors.New("flag provided but not defined: -inf")},
+		{ParseOptions{NegativeNumbers: true, StopAtFirstParameter: true}, "-bool -5 -bool", []string{"-5", "-bool"}, nil},
+		{ParseOptions{NegativeNumbers: true, GNU: true}, "-5 --bool -3.2 -b", []string{"-5", "-3.2"}, nil},
+		{ParseOptions{NegativeNumbers: true, GNU: true}, "-98 -7", []string{"-7"}, nil},
+		{ParseOptions{NegativeNumbers: true, GNU: true, StopAtFirstParameter: true}, "--bool -5 --bool", []string{"-5", "--bool"}, nil},
+	}
+
+	for i, test := range tests {
+		f := newFlagSet("")
+		f.Bool("bool", false, "")
+		f.Bool("b", false, "")
+		f.Int("int", 0, "")
+		f.Bool("9", false, "")
+		f.Bool("8", false, "")
+		f.String("string", "", "")
+
+		params, passthrough, err := ParseArguments(f, strings.Fields(test.args), test.opts)
+		if test.params == nil && test.err == nil {
+			test.params = []string{}
+		}
+		if !reflect.DeepEqual(params, test.params) || passthrough != nil || !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: params, passthrough, err = %q, %q, %v WANT %q, %v", i, params, passthrough, err, test.params, test.err)
+		}
+	}
+}
+
+type parameterStopper bool
+
+func (ps parameterStopper) StopAtFirstParameter() bool {
+	return bool(ps)
+}
+
+func TestStopsAtFirstParameter(t *testing.T) {
+	if StopsAtFirstParameter(nil) || StopsAtFirstParameter(parameterStopper(false)) {
+		t.Error("should not stop")
+	}
+	if !StopsAtFirstParameter(parameterStopper(true)) {
+		t.Error("should stop")
+	}
+}
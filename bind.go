@@ -0,0 +1,392 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Struct field tags used by Bind.
+const (
+	FlagTag    = "flag"
+	UsageTag   = "usage"
+	DefaultTag = "default"
+	ParamTag   = "param"
+)
+
+//Options of ParamTag.
+const (
+	ParamOptional = "optional"
+	ParamMany     = "many"
+)
+
+//Binding is a FlagSetter and ParameterSetter for the fields of a struct.
+//See Bind.
+type Binding struct {
+	flags  []*boundFlag
+	params []*boundParam
+}
+
+type boundFlag struct {
+	name         string
+	usage        string
+	value        reflect.Value
+	defaultValue reflect.Value
+}
+
+type boundParam struct {
+	*Parameter
+	field string
+	usage string
+	value reflect.Value
+}
+
+var (
+	flagValueType = reflect.TypeOf((*flag.Value)(nil)).Elem()
+	durationType  = reflect.TypeOf(time.Duration(0))
+)
+
+//Bind returns a Binding for the tagged fields of the struct pointed to by v.
+//
+//Fields with a FlagTag are flags named by the tag. Their types may be bool, int,
+//int64, uint, uint64, float64, string, time.Duration, or any type whose pointer
+//implements flag.Value. The UsageTag is the flag's usage and the DefaultTag, if
+//present, is parsed as the flag's default value. Otherwise, the field's value
+//at the time of Bind is the default.
+//
+//	Count int `flag:"count" usage:"number of times" default:"1"`
+//
+//Fields with a ParamTag are Parameters in the order of the fields. The tag is
+//the Parameter's name optionally followed by ParamOptional and ParamMany separated
+//by commas. The values of Many Parameters must be slices. Parameter values are
+//converted to the fields' (or slice elements') types when SetParameters is called.
+//The UsageTag of a Parameter is included in ParameterUsage.
+//
+//	Files []string `param:"file,optional,many" usage:"files to read"`
+//
+//Fields of embedded structs are bound as well.
+//The returned error, if not nil, is a *BindError.
+func Bind(v interface{}) (*Binding, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, &BindError{fmt.Sprintf("%T", v), errors.New("must be a pointer to a struct")}
+	}
+
+	b := &Binding{}
+	if err := b.bindStruct(rv.Elem()); err != nil {
+		return nil, err
+	}
+	if err := b.validateParameters(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+//MustBind is the same as Bind but panics if Bind returns an error.
+func MustBind(v interface{}) *Binding {
+	b, err := Bind(v)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (b *Binding) bindStruct(rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		value := rv.Field(i)
+
+		flagName, isFlag := field.Tag.Lookup(FlagTag)
+		paramTag, isParam := field.Tag.Lookup(ParamTag)
+
+		if !isFlag && !isParam {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct && value.CanSet() {
+				if err := b.bindStruct(value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if isFlag && isParam {
+			return &BindError{field.Name, fmt.Errorf("may not have both %s and %s tags", FlagTag, ParamTag)}
+		}
+		if !value.CanSet() {
+			return &BindError{field.Name, errors.New("must be exported")}
+		}
+
+		var err error
+		if isFlag {
+			err = b.bindFlag(field, value, flagName)
+		} else {
+			err = b.bindParam(field, value, paramTag)
+		}
+		if err != nil {
+			return &BindError{field.Name, err}
+		}
+	}
+	return nil
+}
+
+func (b *Binding) bindFlag(field reflect.StructField, value reflect.Value, name string) error {
+	if len(name) == 0 {
+		return fmt.Errorf("%s tag must not be empty", FlagTag)
+	}
+	for _, bf := range b.flags {
+		if bf.name == name {
+			return fmt.Errorf("duplicate flag %q", name)
+		}
+	}
+
+	bf := &boundFlag{
+		name:  name,
+		usage: field.Tag.Get(UsageTag),
+		value: value,
+	}
+	isValue := value.Addr().Type().Implements(flagValueType)
+	if !isValue && !isFlagType(value.Type()) {
+		return fmt.Errorf("unsupported flag type %v", value.Type())
+	}
+
+	bf.defaultValue = reflect.New(value.Type()).Elem()
+	bf.defaultValue.Set(value)
+	if defaultTag, ok := field.Tag.Lookup(DefaultTag); ok {
+		var err error
+		if isValue {
+			err = bf.defaultValue.Addr().Interface().(flag.Value).Set(defaultTag)
+		} else {
+			err = setValue(bf.defaultValue, defaultTag)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid default %q: %v", defaultTag, err)
+		}
+	}
+
+	b.flags = append(b.flags, bf)
+	return nil
+}
+
+func isFlagType(t reflect.Type) bool {
+	if t == durationType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Float64, reflect.String:
+		return t.PkgPath() == ""
+	}
+	return false
+}
+
+func (b *Binding) bindParam(field reflect.StructField, value reflect.Value, tag string) error {
+	parts := strings.Split(tag, ",")
+	param := &Parameter{Name: parts[0]}
+	if len(param.Name) == 0 {
+		return fmt.Errorf("%s tag must have a name", ParamTag)
+	}
+	for _, option := range parts[1:] {
+		switch option {
+		case ParamOptional:
+			param.Optional = true
+		case ParamMany:
+			param.Many = true
+		default:
+			return fmt.Errorf("unknown %s option %q", ParamTag, option)
+		}
+	}
+
+	valueType := value.Type()
+	if param.Many {
+		if valueType.Kind() != reflect.Slice {
+			return fmt.Errorf("%s parameter must be a slice", ParamMany)
+		}
+		valueType = valueType.Elem()
+	}
+	if !isParamType(valueType) {
+		return fmt.Errorf("unsupported parameter type %v", valueType)
+	}
+
+	b.params = append(b.params, &boundParam{
+		Parameter: param,
+		field:     field.Name,
+		usage:     field.Tag.Get(UsageTag),
+		value:     value,
+	})
+	return nil
+}
+
+func isParamType(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(flagValueType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (b *Binding) validateParameters() error {
+	optional := false
+	for i, bp := range b.params {
+		if bp.Many && i != len(b.params)-1 {
+			return &BindError{bp.field, errors.New("only the last parameter may be many")}
+		}
+		if optional && !bp.Optional {
+			return &BindError{bp.field, errors.New("a required parameter may not follow an optional parameter")}
+		}
+		optional = optional || bp.Optional
+	}
+	return nil
+}
+
+//SetFlags defines the bound flags in f.
+//Each flag's field is set to its default value.
+func (b *Binding) SetFlags(f *flag.FlagSet) {
+	for _, bf := range b.flags {
+		bf.value.Set(bf.defaultValue)
+		ptr := bf.value.Addr().Interface()
+
+		if value, ok := ptr.(flag.Value); ok {
+			f.Var(value, bf.name, bf.usage)
+			continue
+		}
+
+		switch ptr := ptr.(type) {
+		case *bool:
+			f.BoolVar(ptr, bf.name, *ptr, bf.usage)
+		case *time.Duration:
+			f.DurationVar(ptr, bf.name, *ptr, bf.usage)
+		case *int:
+			f.IntVar(ptr, bf.name, *ptr, bf.usage)
+		case *int64:
+			f.Int64Var(ptr, bf.name, *ptr, bf.usage)
+		case *uint:
+			f.UintVar(ptr, bf.name, *ptr, bf.usage)
+		case *uint64:
+			f.Uint64Var(ptr, bf.name, *ptr, bf.usage)
+		case *float64:
+			f.Float64Var(ptr, bf.name, *ptr, bf.usage)
+		case *string:
+			f.StringVar(ptr, bf.name, *ptr, bf.usage)
+		}
+	}
+}
+
+//ParameterUsage returns the bound Parameters and the usage of each Parameter
+//that has one on its own line, e.g. "<FILE...> files to read".
+func (b *Binding) ParameterUsage() ([]*Parameter, string) {
+	params := make([]*Parameter, 0, len(b.params))
+	usages := []string{}
+	for _, bp := range b.params {
+		params = append(params, bp.Parameter)
+		if len(bp.usage) > 0 {
+			usages = append(usages, FormatParameter(bp.Parameter)+" "+bp.usage)
+		}
+	}
+	return params, strings.Join(usages, "\n")
+}
+
+//SetParameters converts values to the types of the bound Parameters' fields
+//and sets them in order. A Many Parameter receives all remaining values.
+//
+//A *RequiredParameterNotSetError is returned if a required Parameter does not
+//have a value, ErrTooManyParameters if there are more values than Parameters,
+//and an *InvalidParameterError if a value cannot be converted.
+func (b *Binding) SetParameters(values []string) error {
+	n := len(b.params)
+	if len(values) > n && (n == 0 || !b.params[n-1].Many) {
+		return ErrTooManyParameters
+	}
+
+	for i, bp := range b.params {
+		if i >= len(values) {
+			if !bp.Optional {
+				return &RequiredParameterNotSetError{
+					Name:      bp.Name,
+					Many:      bp.Many,
+					Formatted: FormatParameter(bp.Parameter),
+				}
+			}
+			continue
+		}
+
+		if !bp.Many {
+			if err := setParamValue(bp, bp.value, values[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rest := values[i:]
+		slice := reflect.MakeSlice(bp.value.Type(), len(rest), len(rest))
+		for j, value := range rest {
+			if err := setParamValue(bp, slice.Index(j), value); err != nil {
+				return err
+			}
+		}
+		bp.value.Set(slice)
+	}
+	return nil
+}
+
+func setParamValue(bp *boundParam, v reflect.Value, s string) error {
+	if err := setValue(v, s); err != nil {
+		return &InvalidParameterError{Name: bp.Name, Value: s, Err: err}
+	}
+	return nil
+}
+
+//setValue parses s and sets it in v according to the type of v.
+func setValue(v reflect.Value, s string) error {
+	if value, ok := v.Addr().Interface().(flag.Value); ok {
+		return value.Set(s)
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
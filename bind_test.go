@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindTestValue struct {
+	values []string
+}
+
+func (v *bindTestValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.Join(v.values, ",")
+}
+
+func (v *bindTestValue) Set(value string) error {
+	v.values = append(v.values, value)
+	return nil
+}
+
+type bindTestIntValue int
+
+func (v *bindTestIntValue) String() string {
+	if v == nil {
+		return "0"
+	}
+	return strconv.Itoa(int(*v))
+}
+
+func (v *bindTestIntValue) Set(value string) error {
+	i, err := strconv.Atoi(value)
+	*v = bindTestIntValue(i)
+	return err
+}
+
+type BindTestEmbedded struct {
+	Verbose bool `flag:"v" usage:"verbose output"`
+}
+
+type bindTestStruct struct {
+	BindTestEmbedded
+
+	Count    int           `flag:"count" usage:"number of times" default:"2"`
+	Big      int64         `flag:"big"`
+	Size     uint          `flag:"size"`
+	BigSize  uint64        `flag:"big_size"`
+	Ratio    float64       `flag:"ratio" default:"0.5"`
+	Name     string        `flag:"name"`
+	Timeout  time.Duration `flag:"timeout" default:"1s"`
+	Tags     bindTestValue `flag:"tag" default:"a"`
+	Ignored  string
+	Port     uint16   `param:"port" usage:"port to listen on"`
+	Host     string   `param:"host,optional"`
+	Backends []string `param:"backend,optional,many"`
+}
+
+func TestBind_SetFlagsAndParameters(t *testing.T) {
+	s := &bindTestStruct{Name: "current"}
+	b, err := Bind(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFlagSet("", b)
+	params, err := ParseArgumentsInterspersed(f, strings.Fields("-v 80 -count 3 -timeout 1m -tag b localhost a b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetParameters(params); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &bindTestStruct{
+		BindTestEmbedded: BindTestEmbedded{Verbose: true},
+		Count:            3,
+		Ratio:            0.5,
+		Name:             "current",
+		Timeout:          time.Minute,
+		Tags:             bindTestValue{[]string{"a", "b"}},
+		Port:             80,
+		Host:             "localhost",
+		Backends:         []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Fatalf("bound struct = %+v WANT %+v", s, want)
+	}
+}
+
+func TestBinding_SetFlags_DefinesFlagsWithDefaults(t *testing.T) {
+	b := MustBind(&bindTestStruct{Name: "current"})
+
+	infos := GetFlagInfos(NewFlagSet("", b))
+
+	defaults := map[string]string{}
+	for _, info := range infos {
+		defaults[info.Name] = info.Default
+	}
+	want := map[string]string{
+		"v":        "",
+		"count":    "2",
+		"big":      "",
+		"size":     "",
+		"big_size": "",
+		"ratio":    "0.5",
+		"name":     "current",
+		"timeout":  "1s",
+		"tag":      "a",
+	}
+	if !reflect.DeepEqual(defaults, want) {
+		t.Fatalf("defaults = %v WANT %v", defaults, want)
+	}
+}
+
+func TestBinding_SetFlags_ResetsFieldsToDefaults(t *testing.T) {
+	s := &bindTestStruct{}
+	b := MustBind(s)
+
+	if _, err := ParseArgumentsInterspersed(NewFlagSet("", b), strings.Fields("-count 3 -tag b")); err != nil {
+		t.Fatal(err)
+	}
+	NewFlagSet("", b)
+
+	if s.Count != 2 || !reflect.DeepEqual(s.Tags, bindTestValue{[]string{"a"}}) {
+		t.Fatalf("count, tags = %v, %v WANT 2, a", s.Count, s.Tags.values)
+	}
+}
+
+func TestBinding_ParameterUsage(t *testing.T) {
+	b := MustBind(&bindTestStruct{})
+
+	params, usage := b.ParameterUsage()
+
+	wantParams := []*Parameter{
+		{Name: "port"},
+		{Name: "host", Optional: true},
+		{Name: "backend", Optional: true, Many: true},
+	}
+	if !reflect.DeepEqual(params, wantParams) {
+		t.Errorf("params = %v WANT %v", params, wantParams)
+	}
+	if usage != "<PORT> port to listen on" {
+		t.Errorf("usage = %q", usage)
+	}
+}
+
+func TestBinding_SetParameters_Errors(t *testing.T) {
+	tests := []struct {
+		v      interface{}
+		values []string
+		err    error
+	}{
+		{
+			&bindTestStruct{},
+			nil,
+			&RequiredParameterNotSetError{Name: "port", Formatted: "<PORT>"},
+		},
+		{
+			&bindTestStruct{},
+			[]string{"http"},
+			&InvalidParameterError{Name: "port", Value: "http", Err: errors.New(`strconv.ParseUint: parsing "http": invalid syntax`)},
+		},
+		{
+			&struct {
+				A string `param:"a"`
+			}{},
+			[]string{"1", "2"},
+			ErrTooManyParameters,
+		},
+		{
+			&struct{}{},
+			[]string{"1"},
+			ErrTooManyParameters,
+		},
+		{
+			&struct {
+				A []int `param:"a,many"`
+			}{},
+			[]string{"1", "two"},
+			&InvalidParameterError{Name: "a", Value: "two", Err: errors.New(`strconv.ParseInt: parsing "two": invalid syntax`)},
+		},
+	}
+
+	for i, test := range tests {
+		err := MustBind(test.v).SetParameters(test.values)
+
+		if err == nil || test.err == nil || err.Error() != test.err.Error() || reflect.TypeOf(err) != reflect.TypeOf(test.err) {
+			t.Errorf("%v: SetParameters() = %#v WANT %#v", i, err, test.err)
+		}
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	tests := []struct {
+		v   interface{}
+		err string
+	}{
+		{struct{}{}, "cannot bind field struct {}: must be a pointer to a struct"},
+		{&struct {
+			a int `flag:"a"`
+		}{}, "cannot bind field a: must be exported"},
+		{&struct {
+			A int `flag:"a" param:"a"`
+		}{}, "cannot bind field A: may not have both flag and param tags"},
+		{&struct {
+			A int `flag:""`
+		}{}, "cannot bind field A: flag tag must not be empty"},
+		{&struct {
+			A int `flag:"a"`
+			B int `flag:"a"`
+		}{}, `cannot bind field B: duplicate flag "a"`},
+		{&struct {
+			A int32 `flag:"a"`
+		}{}, "cannot bind field A: unsupported flag type int32"},
+		{&struct {
+			A int `flag:"a" default:"one"`
+		}{}, `cannot bind field A: invalid default "one": strconv.ParseInt: parsing "one": invalid syntax`},
+		{&struct {
+			A bindTestIntValue `flag:"a" default:"one"`
+		}{}, `cannot bind field A: invalid default "one": strconv.Atoi: parsing "one": invalid syntax`},
+		{&struct {
+			A string `param:""`
+		}{}, "cannot bind field A: param tag must have a name"},
+		{&struct {
+			A string `param:"a,other"`
+		}{}, `cannot bind field A: unknown param option "other"`},
+		{&struct {
+			A string `param:"a,many"`
+		}{}, "cannot bind field A: many parameter must be a slice"},
+		{&struct {
+			A []chan int `param:"a,many"`
+		}{}, "cannot bind field A: unsupported parameter type chan int"},
+		{&struct {
+			A []string `param:"a,many"`
+			B string   `param:"b"`
+		}{}, "cannot bind field A: only the last parameter may be many"},
+		{&struct {
+			A string `param:"a,optional"`
+			B string `param:"b"`
+		}{}, "cannot bind field B: a required parameter may not follow an optional parameter"},
+	}
+
+	for i, test := range tests {
+		_, err := Bind(test.v)
+
+		if err == nil || err.Error() != test.err {
+			t.Errorf("%v: Bind() error = %v WANT %v", i, err, test.err)
+		}
+		if _, ok := err.(*BindError); !ok {
+			t.Errorf("%v: Bind() error = %#v WANT *BindError", i, err)
+		}
+	}
+}
+
+func TestMustBind_Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustBind() should panic")
+		}
+	}()
+
+	MustBind(struct{}{})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"io/ioutil"
+	"reflect"
 	"sort"
 	"strings"
 )
@@ -67,12 +68,27 @@ func CountFlags(f *flag.FlagSet) int {
 }
 
 //GetFlagSetDefaults returns the result of f.PrintDefaults() with the optionally
-//trailing "\n" removed.
+//trailing "\n" removed. Optional value flags are rendered as "-color[=WHEN]"
+//instead of "-color WHEN". See OptionalValueFlag.
 func GetFlagSetDefaults(f *flag.FlagSet) string {
 	out := bytes.NewBuffer([]byte{})
 	f.SetOutput(out)
 	f.PrintDefaults()
-	return strings.TrimRight(out.String(), "\n")
+
+	optional := map[string]string{}
+	f.VisitAll(func(fl *flag.Flag) {
+		if IsOptionalValueFlag(fl) {
+			valueName, _ := flag.UnquoteUsage(fl)
+			optional["  -"+fl.Name+" "+valueName] = "  -" + fl.Name + "[=" + valueName + "]"
+		}
+	})
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	for i, line := range lines {
+		if replacement, ok := optional[line]; ok {
+			lines[i] = replacement
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 //GetJoinedNameSortedAliases returns name followed by the cloned and sorted aliases
@@ -85,3 +101,74 @@ func GetJoinedNameSortedAliases(name string, aliases []string) string {
 	all := append([]string{name}, toSort...)
 	return strings.Join(all, ", ")
 }
+
+//FlagInfo describes a flag for help and documentation output.
+type FlagInfo struct {
+	//Name is the name of the flag without a leading "-".
+	Name string
+
+	//ValueName is the name of the flag's value as returned by flag.UnquoteUsage.
+	//It is the empty string for boolean flags.
+	ValueName string
+
+	//Type is the name of the flag's type, e.g. "int" or "duration", as returned
+	//by flag.UnquoteUsage without considering a back-quoted name in the usage.
+	//It is "bool" for boolean flags and "value" for flags of unknown types.
+	Type string
+
+	//Usage is the flag's usage as returned by flag.UnquoteUsage.
+	Usage string
+
+	//Default is the flag's default value.
+	//It is the empty string if the default is the zero value of the flag's type,
+	//in which case flag.FlagSet.PrintDefaults does not print a default.
+	Default string
+
+	//Bool denotes whether or not the flag is a boolean flag.
+	Bool bool
+}
+
+//GetFlagInfos returns a FlagInfo for each flag in f in lexicographical order.
+func GetFlagInfos(f *flag.FlagSet) []*FlagInfo {
+	infos := []*FlagInfo{}
+	f.VisitAll(func(fl *flag.Flag) {
+		valueName, usage := flag.UnquoteUsage(fl)
+		info := &FlagInfo{
+			Name:      fl.Name,
+			ValueName: valueName,
+			Type:      getFlagType(fl),
+			Usage:     usage,
+			Bool:      IsBoolFlag(fl),
+		}
+		if !isZeroValue(fl) {
+			info.Default = fl.DefValue
+		}
+		infos = append(infos, info)
+	})
+	return infos
+}
+
+func getFlagType(fl *flag.Flag) string {
+	if IsBoolFlag(fl) {
+		return "bool"
+	}
+	typ, _ := flag.UnquoteUsage(&flag.Flag{Value: fl.Value})
+	return typ
+}
+
+//isZeroValue determines whether fl.DefValue represents the zero value of fl's
+//type in the same way that flag.FlagSet.PrintDefaults does.
+func isZeroValue(fl *flag.Flag) bool {
+	typ := reflect.TypeOf(fl.Value)
+	var z reflect.Value
+	if typ.Kind() == reflect.Ptr {
+		z = reflect.New(typ.Elem())
+	} else {
+		z = reflect.Zero(typ)
+	}
+	value, ok := z.Interface().(flag.Value)
+	if !ok {
+		return fl.DefValue == ""
+	}
+	return fl.DefValue == value.String()
+}
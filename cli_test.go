@@ -93,3 +93,23 @@ type MockFlagSetter struct {
 func (fs *MockFlagSetter) SetFlags(f *flag.FlagSet) {
 	fs.calledWith = f
 }
+
+func TestGetFlagInfos(t *testing.T) {
+	f := NewFlagSet("", nil)
+	f.String("s", "", "a `name` usage")
+	f.Int("i", 2, "int usage")
+	f.Bool("b", false, "bool usage")
+	f.Bool("t", true, "true usage")
+
+	infos := GetFlagInfos(f)
+
+	want := []*FlagInfo{
+		{Name: "b", ValueName: "", Type: "bool", Usage: "bool usage", Bool: true},
+		{Name: "i", ValueName: "int", Type: "int", Usage: "int usage", Default: "2"},
+		{Name: "s", ValueName: "name", Type: "string", Usage: "a name usage"},
+		{Name: "t", ValueName: "", Type: "bool", Usage: "true usage", Default: "true", Bool: true},
+	}
+	if !reflect.DeepEqual(infos, want) {
+		t.Fatalf("GetFlagInfos() = %v WANT %v", infos, want)
+	}
+}
@@ -0,0 +1,139 @@
+//Command clidiff reports the differences between two JSON specs exported from
+//a Commander or SubCommander.
+//
+//Breaking changes and additive changes are printed separately.
+//The exit status is 1 if there are breaking changes and 2 if an error occurs.
+//
+//	usage: clidiff [options...] <OLD> <NEW>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/command"
+	"github.com/gogolfing/cli/spec"
+)
+
+//ExitBreaking is the exit status when there are breaking changes.
+const ExitBreaking = 1
+
+//ExitError is the exit status when an error occurs.
+const ExitError = 2
+
+//ErrBothStdin is returned when both OLD and NEW are "-".
+var ErrBothStdin = errors.New("OLD and NEW may not both be - for standard input")
+
+func main() {
+	c := &command.Commander{
+		Name:    os.Args[0],
+		Command: &diffCommand{},
+	}
+
+	if err := c.Execute(os.Args[1:]); err != nil {
+		os.Exit(getExitStatus(err, os.Stderr))
+	}
+}
+
+//getExitStatus returns the exit status for an error returned from a Commander
+//and writes the error to outErr if the Commander has not already done so.
+func getExitStatus(err error, outErr io.Writer) int {
+	ece, ok := err.(*command.ExecutingCommandError)
+	if !ok {
+		return ExitError
+	}
+	if ese, ok := ece.Err.(*cli.ExitStatusError); ok {
+		return ese.Code
+	}
+	fmt.Fprintln(outErr, ece.Err)
+	return ExitError
+}
+
+type diffCommand struct {
+	quiet bool
+
+	oldPath string
+	newPath string
+}
+
+func (d *diffCommand) Description() string {
+	return "Reports breaking and additive changes between the JSON specs OLD and NEW"
+}
+
+func (d *diffCommand) SetFlags(f *flag.FlagSet) {
+	f.BoolVar(&d.quiet, "q", false, "do not print changes, only set the exit status")
+}
+
+func (d *diffCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return []*cli.Parameter{{Name: "old"}, {Name: "new"}},
+		"<OLD> and <NEW> are files containing JSON specs, or - for standard input"
+}
+
+func (d *diffCommand) SetParameters(params []string) error {
+	if len(params) < 2 {
+		name := []string{"old", "new"}[len(params)]
+		return &cli.RequiredParameterNotSetError{
+			Name:      name,
+			Formatted: command.FormatParameter(&cli.Parameter{Name: name}),
+		}
+	}
+	if len(params) > 2 {
+		return cli.ErrTooManyParameters
+	}
+
+	if params[0] == "-" && params[1] == "-" {
+		return ErrBothStdin
+	}
+
+	d.oldPath, d.newPath = params[0], params[1]
+	return nil
+}
+
+func (d *diffCommand) Execute(_ context.Context, in io.Reader, out, _ io.Writer) error {
+	old, err := readSpec(d.oldPath, in)
+	if err != nil {
+		return err
+	}
+	new, err := readSpec(d.newPath, in)
+	if err != nil {
+		return err
+	}
+
+	diff := spec.Compare(old, new)
+	if !d.quiet {
+		if err := diff.Write(out); err != nil {
+			return err
+		}
+	}
+
+	if len(diff.Breaking) > 0 {
+		return &cli.ExitStatusError{
+			Code: ExitBreaking,
+			Err:  fmt.Errorf("%d breaking changes", len(diff.Breaking)),
+		}
+	}
+	return nil
+}
+
+func readSpec(path string, in io.Reader) (*spec.Command, error) {
+	if path == "-" {
+		return spec.Decode(in)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	c, err := spec.Decode(file)
+	if specErr, ok := err.(*spec.Error); ok {
+		specErr.Location = path + ":" + specErr.Location
+	}
+	return c, err
+}
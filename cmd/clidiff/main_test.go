@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/command"
+)
+
+func TestDiffCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "clidiff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+	ioutil.WriteFile(oldPath, []byte(`{"name": "prog", "sub_commands": [{"name": "a"}]}`), 0644)
+	ioutil.WriteFile(newPath, []byte(`{"name": "prog", "sub_commands": [{"name": "b"}]}`), 0644)
+
+	tests := []struct {
+		args   []string
+		in     string
+		out    string
+		status int
+	}{
+		{
+			[]string{oldPath, newPath},
+			"",
+			"Breaking changes:\n  removed sub-command \"a\"\n\nAdditive changes:\n  added sub-command \"b\"\n",
+			ExitBreaking,
+		},
+		{
+			[]string{"-q", oldPath, newPath},
+			"",
+			"",
+			ExitBreaking,
+		},
+		{
+			[]string{newPath, "-"},
+			`{"name": "prog", "sub_commands": [{"name": "b"}, {"name": "c"}]}`,
+			"Additive changes:\n  added sub-command \"c\"\n",
+			0,
+		},
+	}
+
+	for i, test := range tests {
+		out, outErr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+		c := &command.Commander{Name: "clidiff", Command: &diffCommand{}}
+
+		err := c.ExecuteContext(context.Background(), test.args, strings.NewReader(test.in), out, outErr)
+
+		status := 0
+		if err != nil {
+			status = getExitStatus(err, outErr)
+		}
+		if status != test.status {
+			t.Errorf("%v: status = %v WANT %v (%v)", i, status, test.status, err)
+		}
+		if out.String() != test.out {
+			t.Errorf("%v: out = %q WANT %q", i, out, test.out)
+		}
+	}
+}
+
+func TestDiffCommand_ErrorsExitWithExitError(t *testing.T) {
+	tests := [][]string{
+		{"old.json"},
+		{"does-not-exist.json", "-"},
+	}
+
+	for i, args := range tests {
+		out, outErr := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+		c := &command.Commander{Name: "clidiff", Command: &diffCommand{}}
+
+		err := c.ExecuteContext(context.Background(), args, strings.NewReader("{}"), out, outErr)
+
+		if status := getExitStatus(err, outErr); status != ExitError {
+			t.Errorf("%v: status = %v WANT %v", i, status, ExitError)
+		}
+		if outErr.Len() == 0 {
+			t.Errorf("%v: outErr should not be empty", i)
+		}
+	}
+}
+
+func TestGetExitStatus(t *testing.T) {
+	outErr := bytes.NewBuffer([]byte{})
+	err := &command.ExecutingCommandError{Err: &cli.ExitStatusError{Code: 3, Err: errors.New("error")}}
+
+	if status := getExitStatus(err, outErr); status != 3 || outErr.Len() != 0 {
+		t.Fatalf("getExitStatus() = %v, %q", status, outErr)
+	}
+}
+
+func TestDiffCommand_SetParametersRejectsBothStdin(t *testing.T) {
+	if err := (&diffCommand{}).SetParameters([]string{"-", "-"}); err != ErrBothStdin {
+		t.Fatalf("SetParameters() = %v WANT %v", err, ErrBothStdin)
+	}
+}
@@ -65,3 +65,12 @@ type Command interface {
 	///use.
 	Execute(ctx context.Context, in io.Reader, out, outErr io.Writer) error
 }
+
+//UsageLiner is an optional interface that Commands may implement in order to
+//provide the usage line of help and error output instead of the one generated
+//from their options and parameters.
+type UsageLiner interface {
+	//UsageLine returns the usage line without the leading Usage value, e.g.
+	//"prog [-v] <src> <dst>...".
+	UsageLine() string
+}
@@ -25,6 +25,13 @@ type CommandStruct struct {
 
 	//ExecuteValue is used as the Command's implementation if not nil.
 	ExecuteValue func(context.Context, io.Reader, io.Writer, io.Writer) error
+
+	//StopAtFirstParameterValue is returned from Command's cli.ParameterStopper
+	//implementation.
+	StopAtFirstParameterValue bool
+
+	//CompleteValue is used as the Command's cli.Completer implementation if not nil.
+	CompleteValue func(*cli.Completion) []string
 }
 
 //Description returns cs.DescriptionValue.
@@ -66,3 +73,18 @@ func (cs *CommandStruct) Execute(ctx context.Context, in io.Reader, out, outErr
 	}
 	return nil
 }
+
+//Complete calls and returns the result from cs.CompleteValue(c) if the field
+//is not nil.
+//Otherwise, it returns nil.
+func (cs *CommandStruct) Complete(c *cli.Completion) []string {
+	if cs.CompleteValue != nil {
+		return cs.CompleteValue(c)
+	}
+	return nil
+}
+
+//StopAtFirstParameter returns cs.StopAtFirstParameterValue.
+func (cs *CommandStruct) StopAtFirstParameter() bool {
+	return cs.StopAtFirstParameterValue
+}
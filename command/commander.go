@@ -21,6 +21,51 @@ type Commander struct {
 
 	//Command is the Command to execute.
 	Command
+
+	//GNUOptions, if true, parses arguments with cli.ParseArgumentsGNU instead of
+	//cli.ParseArgumentsInterspersed. Help output then lists the names of each
+	//option together, e.g. "-v, --verbose".
+	GNUOptions bool
+
+	//NegativeNumbers, if true, parses arguments that are negative numbers, e.g. -5
+	//or -3.2, as parameters unless a flag of that name is defined.
+	//Help output then documents this with cli.NegativeNumbersUsage.
+	NegativeNumbers bool
+
+	//ResponseFiles, if true, replaces each argument of the form @path by the
+	//arguments in the file at path before parsing. See cli.ExpandResponseFiles.
+	ResponseFiles bool
+
+	//EnvPrefix, if not empty, allows flags that are not present in the arguments
+	//to take their values from environment variables named by cli.EnvName(EnvPrefix,
+	//<flag name>), e.g. PROG_TOKEN for the flag -token.
+	//These names are included in the options of help output.
+	EnvPrefix string
+
+	//EnvOpts, if true and EnvPrefix is not empty, prepends default arguments from
+	//the environment variable named cli.EnvName(EnvPrefix, cli.EnvOptsName),
+	//e.g. PROG_OPTS, to the arguments. Its value is split by cli.SplitArgs.
+	//The arguments take precedence as they are parsed after the default arguments.
+	//The flags set only by default arguments have the cli.SourceEnv of the variable
+	//in the execution's cli.Provenance.
+	EnvOpts bool
+
+	//LookupEnv is used to look up environment variables if not nil.
+	//Otherwise, os.LookupEnv is used.
+	LookupEnv func(key string) (string, bool)
+
+	//ConfigFiles are configuration files that provide values for flags that are
+	//not present in the arguments or the environment.
+	//Only the unnamed section of the files is used. See cli.Config for the format.
+	ConfigFiles cli.ConfigFiles
+
+	//OptionsFlag, if not empty, is the name of a boolean flag, e.g. "show-options",
+	//that is defined along with the flags of c.Command. If it is set, then a table
+	//of each option's value and source is written to out instead of executing
+	//c.Command.
+	//The table is that of the cli.Provenance that is otherwise available to
+	//c.Command.Execute through cli.ProvenanceFromContext.
+	OptionsFlag string
 }
 
 //Execute is syntactic sugar for ExecuteContext() with context.Background(), args,
@@ -31,10 +76,14 @@ func (c *Commander) Execute(args []string) error {
 
 //ExecuteContext executes c.Command with the provided parameters.
 //
-//Ctx is the Context passed unaltered to c.Command.Execute.
+//Ctx is passed to c.Command.Execute carrying the cli.Provenance of the Command's
+//flags. See cli.ProvenanceFromContext.
 //
 //Args should be the program arguments excluding the program name - usually os.Args[1:].
-//They will be parsed using cli.ParseArgumentsInterspersed.
+//They will be parsed using cli.ParseArgumentsInterspersed, or cli.ParseArgumentsGNU
+//if c.GNUOptions is true. If c.Command is a cli.ParameterStopper that stops at
+//its first parameter, then the parameters are the arguments after the flags,
+//unaltered.
 //
 //The parameters in, out, and outErr are passed unaltered to c.Command.Execute
 //and should represent the standard input, output, and error files for the executing
@@ -66,24 +115,168 @@ func (c *Commander) ExecuteContext(ctx context.Context, args []string, in io.Rea
 	return err
 }
 
+//Complete returns completion candidates for the last of args if c.Command implements
+//cli.Completer. The arguments before the last are used to determine whether
+//a flag or parameter value is being completed, parsed in the same way as when
+//c is executed.
+//
+//Args should be the program arguments excluding the program name and include
+//the (possibly empty) word being completed.
+func (c *Commander) Complete(args []string) []string {
+	completer, ok := c.Command.(cli.Completer)
+	if !ok || len(args) == 0 {
+		return nil
+	}
+	n := len(args) - 1
+	return cli.Complete(completer, cli.NewFlagSet(c.Name, c), args[:n], args[n], c.getParseOptions())
+}
+
+//SetFlags sets the flags of c.Command and c.OptionsFlag on f.
+//If c.EnvPrefix is not empty, then the flags' usages include their environment
+//variable names.
+func (c *Commander) SetFlags(f *flag.FlagSet) {
+	c.envFlagSetter(nil).SetFlags(f)
+}
+
+func (c *Commander) envFlagSetter(names map[string]string) cli.FlagSetter {
+	fs := flagSetterFunc(c.setFlags)
+	if len(c.EnvPrefix) == 0 {
+		return fs
+	}
+	return &cli.EnvFlagSetter{
+		FlagSetter: fs,
+		Prefix:     c.EnvPrefix,
+		Names:      names,
+	}
+}
+
+func (c *Commander) setFlags(f *flag.FlagSet) {
+	c.Command.SetFlags(f)
+	if len(c.OptionsFlag) > 0 {
+		f.Bool(c.OptionsFlag, false, cli.OptionsFlagUsage)
+	}
+}
+
 func (c *Commander) executeContext(ctx context.Context, args []string, in io.Reader, out, outErr io.Writer) error {
-	f := cli.NewFlagSet(c.Name, c)
+	envNames := map[string]string{}
+	f := cli.NewFlagSet(c.Name, c.envFlagSetter(envNames))
 
-	params, err := cli.ParseArgumentsInterspersed(f, args)
+	args, err := c.expandResponseFiles(args)
+	if err != nil {
+		return &ParsingCommandError{err}
+	}
+	envArgs, err := c.getEnvOpts()
+	if err != nil {
+		return &ParsingCommandError{err}
+	}
+	params, passthrough, err := c.parseArguments(f, append(append([]string{}, envArgs...), args...))
 	if err != nil {
 		return &ParsingCommandError{err}
 	}
+	p := cli.NewProvenance()
+	p.AddFlags(f)
+	p.RecordEnvArgs(f, c.getEnvOptsName(), envArgs, args, c.getParseOptions())
+	if err := cli.SetFlagsFromEnv(f, envNames, c.LookupEnv, p); err != nil {
+		return &ParsingCommandError{err}
+	}
+	if err := c.setFlagsFromConfig(f, p); err != nil {
+		return &ParsingCommandError{err}
+	}
+	if err := cli.ReadSecrets(in, f); err != nil {
+		return &ParsingCommandError{err}
+	}
 	if err := c.SetParameters(params); err != nil {
 		return &ParsingCommandError{err}
 	}
+	if ps, ok := c.Command.(cli.PassthroughSetter); ok {
+		if err := ps.SetPassthrough(passthrough); err != nil {
+			return &ParsingCommandError{err}
+		}
+	}
+
+	if isOptionsFlagSet(p, c.OptionsFlag) {
+		if err := p.WriteTable(out); err != nil {
+			return &ExecutingCommandError{err}
+		}
+		return nil
+	}
 
-	if err := c.Command.Execute(ctx, in, out, outErr); err != nil {
+	if err := c.Command.Execute(cli.NewProvenanceContext(ctx, p), in, out, outErr); err != nil {
 		return &ExecutingCommandError{err}
 	}
 
 	return nil
 }
 
+//parseArguments parses args with the parser selected by c.GNUOptions,
+//c.NegativeNumbers, and whether or not c.Command stops at its first parameter.
+//The arguments after "--" are returned in passthrough only if c.Command is a
+//cli.PassthroughSetter.
+func (c *Commander) parseArguments(f *flag.FlagSet, args []string) (params, passthrough []string, err error) {
+	return cli.ParseArguments(f, args, c.getParseOptions())
+}
+
+//getParseOptions returns the cli.ParseOptions used by parseArguments.
+func (c *Commander) getParseOptions() cli.ParseOptions {
+	_, split := c.Command.(cli.PassthroughSetter)
+	return cli.ParseOptions{
+		GNU:                  c.GNUOptions,
+		StopAtFirstParameter: cli.StopsAtFirstParameter(c.Command),
+		Passthrough:          split,
+		NegativeNumbers:      c.NegativeNumbers,
+	}
+}
+
+//expandResponseFiles returns args with response files expanded if c.ResponseFiles
+//is true.
+func (c *Commander) expandResponseFiles(args []string) ([]string, error) {
+	if !c.ResponseFiles {
+		return args, nil
+	}
+	return cli.ExpandResponseFiles(args)
+}
+
+//getEnvOpts returns the default arguments of c.EnvOpts, which precede the
+//arguments of an execution.
+func (c *Commander) getEnvOpts() ([]string, error) {
+	if !c.EnvOpts || len(c.EnvPrefix) == 0 {
+		return []string{}, nil
+	}
+	return cli.EnvArgs(c.getEnvOptsName(), c.LookupEnv)
+}
+
+//getEnvOptsName returns the name of the environment variable of c.EnvOpts.
+func (c *Commander) getEnvOptsName() string {
+	return cli.EnvName(c.EnvPrefix, cli.EnvOptsName)
+}
+
+func (c *Commander) setFlagsFromConfig(f *flag.FlagSet, p *cli.Provenance) error {
+	configs, err := c.ConfigFiles.Load()
+	if err != nil {
+		return err
+	}
+	err = cli.CheckConfigSections(configs, func(_ string) bool {
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	return cli.SetFlagsFromConfig(f, configs, "", nil, p)
+}
+
+//isOptionsFlagSet returns whether or not the options flag named name in p has
+//the value true.
+func isOptionsFlagSet(p *cli.Provenance, name string) bool {
+	option := p.Lookup(name)
+	return len(name) > 0 && option != nil && option.Flag.Value.String() == "true"
+}
+
+type flagSetterFunc func(f *flag.FlagSet)
+
+func (fs flagSetterFunc) SetFlags(f *flag.FlagSet) {
+	fs(f)
+}
+
 func (c *Commander) printCommandError(out io.Writer, err error, description bool) {
 	if err != nil {
 		fmt.Fprintf(out, "%v\n\n", err)
@@ -103,20 +296,22 @@ func (c *Commander) maybePrintHeader(out io.Writer) {
 }
 
 func (c *Commander) printCommandUsage(out io.Writer) {
-	fmt.Fprintf(out, "%s %s", Usage, c.Name)
-
-	c.maybePrintCommandLineUsage(out)
-
-	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s %s\n", Usage, c.getCommandUsage(c.Name))
 
 	c.maybePrintOptionsUsage(out)
 	c.maybePrintParameterUsage(out)
 }
 
-func (c *Commander) maybePrintCommandLineUsage(out io.Writer) {
+//getCommandUsage returns name followed by the available command line arguments
+//or the UsageLine of c.Command if it is a UsageLiner.
+func (c *Commander) getCommandUsage(name string) string {
+	if usageLiner, ok := c.Command.(UsageLiner); ok {
+		return usageLiner.UsageLine()
+	}
 	if commandLineUsage := c.getCommandLineUsage(); len(commandLineUsage) > 0 {
-		fmt.Fprintf(out, " %s", commandLineUsage)
+		return name + " " + commandLineUsage
 	}
+	return name
 }
 
 func (c *Commander) getCommandLineUsage() string {
@@ -146,7 +341,12 @@ func (c *Commander) maybePrintOptionsUsage(out io.Writer) {
 	if !c.hasOptions() {
 		return
 	}
-	fmt.Fprintf(out, "\n%s:\n%s\n", OptionsName, cli.GetFlagSetDefaults(cli.NewFlagSet(c.Name, c)))
+	f := cli.NewFlagSet(c.Name, c)
+	defaults := cli.GetFlagSetDefaults(f)
+	if c.GNUOptions {
+		defaults = cli.GetGNUFlagSetDefaults(f)
+	}
+	fmt.Fprintf(out, "\n%s:\n%s\n", OptionsName, defaults)
 }
 
 func (c *Commander) maybePrintParameterUsage(out io.Writer) {
@@ -161,6 +361,10 @@ func (c *Commander) maybePrintParameterUsage(out io.Writer) {
 		fmt.Fprintf(out, "\n%s", usage)
 		didPrint = true
 	}
+	if c.NegativeNumbers {
+		fmt.Fprintf(out, "\n%s", cli.NegativeNumbersUsage)
+		didPrint = true
+	}
 	if didPrint {
 		fmt.Fprintln(out)
 	}
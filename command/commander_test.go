@@ -7,7 +7,9 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -202,7 +204,7 @@ func TestCommander_ExecuteContext_WorksCorrectly(t *testing.T) {
 				},
 				ExecuteValue: func(actualCtx context.Context, in io.Reader, out io.Writer, outErr io.Writer) error {
 					executeCalled = true
-					if actualCtx != ctx {
+					if actualCtx.Value(0) != 1 || cli.ProvenanceFromContext(actualCtx) == nil {
 						t.Error("did not receive correct context in execute method")
 					}
 					if actualIn != in {
@@ -319,3 +321,561 @@ func executeContext(c *Commander, ctx context.Context, args []string, in io.Read
 
 	return out, outErr, err
 }
+
+func TestCommander_Complete(t *testing.T) {
+	c := &Commander{
+		Name: "command",
+		Command: &CommandStruct{
+			FlagSetter: clitest.NewStringsFlagSetter("format"),
+			CompleteValue: func(c *cli.Completion) []string {
+				if c.Flag == "format" {
+					return []string{"json", "text"}
+				}
+				return []string{fmt.Sprint(len(c.Parameters))}
+			},
+		},
+	}
+
+	tests := []struct {
+		args   []string
+		result []string
+	}{
+		{nil, nil},
+		{[]string{""}, []string{"0"}},
+		{[]string{"a", "b", ""}, []string{"2"}},
+		{[]string{"a", "-format", "j"}, []string{"json"}},
+	}
+
+	for i, test := range tests {
+		result := c.Complete(test.args)
+
+		if (len(result) != 0 || len(test.result) != 0) && !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%v: Complete() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+func TestCommander_Complete_GNUOptions(t *testing.T) {
+	c := &Commander{
+		Name: "command",
+		Command: &CommandStruct{
+			FlagSetter: clitest.NewStringsFlagSetter("f", "format"),
+			CompleteValue: func(c *cli.Completion) []string {
+				if len(c.Flag) > 0 {
+					return []string{c.Flag + "-json", c.Flag + "-text"}
+				}
+				return []string{fmt.Sprint(len(c.Parameters))}
+			},
+		},
+		GNUOptions: true,
+	}
+
+	tests := []struct {
+		args   []string
+		result []string
+	}{
+		{[]string{"a", "-f", "f-j"}, []string{"f-json"}},
+		{[]string{"a", "--form", ""}, []string{"format-json", "format-text"}},
+		{[]string{"--format=format-t"}, []string{"--format=format-text"}},
+		{[]string{"-ff-t"}, []string{"-ff-text"}},
+		{[]string{"-format", ""}, []string{"0"}},
+	}
+
+	for i, test := range tests {
+		result := c.Complete(test.args)
+
+		if !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%v: Complete() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+func TestCommander_ExecuteContext_SetsFlagsFromEnvironment(t *testing.T) {
+	fs := &clitest.SimpleFlagSetter{}
+	env := map[string]string{"PROG_INT": "1", "PROG_STRING": "env"}
+
+	ct := &CommanderTest{
+		Commander: &Commander{
+			Command: &CommandStruct{
+				FlagSetter: fs,
+			},
+			EnvPrefix: "prog",
+			LookupEnv: func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			},
+		},
+		Args: strings.Fields("-string arg"),
+	}
+
+	testCommanderTest(t, ct)
+
+	if !reflect.DeepEqual(fs, &clitest.SimpleFlagSetter{Int: 1, String: "arg"}) {
+		t.Fatalf("flags = %v", fs)
+	}
+}
+
+func TestCommander_ExecuteContext_InvalidEnvironmentValue(t *testing.T) {
+	c := &Commander{
+		Name: "command",
+		Command: &CommandStruct{
+			FlagSetter: &clitest.SimpleFlagSetter{},
+		},
+		EnvPrefix: "prog",
+		LookupEnv: func(key string) (string, bool) {
+			return "one", key == "PROG_INT"
+		},
+	}
+
+	out, _, err := executeContext(c, nil, nil, nil)
+
+	pce, ok := err.(*ParsingCommandError)
+	if !ok {
+		t.Fatalf("err = %v", err)
+	}
+	if _, ok := pce.Err.(*cli.EnvValueError); !ok {
+		t.Fatalf("err = %v", pce.Err)
+	}
+	if out.Len() != 0 {
+		t.Fatal(out.String())
+	}
+}
+
+func TestCommander_ExecuteContext_PrependsEnvOpts(t *testing.T) {
+	env := map[string]string{"PROG_OPTS": "-int 1 -string 'env opts'", "PROG_INT": "2"}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	tests := []struct {
+		envOpts bool
+		args    string
+		flags   *clitest.SimpleFlagSetter
+	}{
+		{false, "", &clitest.SimpleFlagSetter{Int: 2}},
+		{true, "", &clitest.SimpleFlagSetter{Int: 1, String: "env opts"}},
+		{true, "-string arg -bool", &clitest.SimpleFlagSetter{Int: 1, String: "arg", Bool: true}},
+	}
+
+	for i, test := range tests {
+		fs := &clitest.SimpleFlagSetter{}
+		testCommanderTest(t, &CommanderTest{
+			Commander: &Commander{
+				Command:   &CommandStruct{FlagSetter: fs},
+				EnvPrefix: "prog",
+				EnvOpts:   test.envOpts,
+				LookupEnv: lookupEnv,
+			},
+			Args: strings.Fields(test.args),
+		})
+
+		if !reflect.DeepEqual(fs, test.flags) {
+			t.Errorf("%v: flags = %+v WANT %+v", i, fs, test.flags)
+		}
+	}
+
+	env["PROG_OPTS"] = `-string "unterminated`
+	_, _, err := executeContext(&Commander{
+		Command:   &CommandStruct{FlagSetter: &clitest.SimpleFlagSetter{}},
+		EnvPrefix: "prog",
+		EnvOpts:   true,
+		LookupEnv: lookupEnv,
+	}, nil, nil, nil)
+	want := &ParsingCommandError{&cli.EnvValueError{Name: "PROG_OPTS", Value: env["PROG_OPTS"], Err: cli.ErrUnterminatedQuote}}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("err = %v WANT %v", err, want)
+	}
+}
+
+func TestCommander_SetFlags_IncludesEnvironmentNamesInUsage(t *testing.T) {
+	c := &Commander{
+		Command: &CommandStruct{
+			FlagSetter: &clitest.SimpleFlagSetter{},
+		},
+		EnvPrefix: "prog",
+	}
+
+	f := cli.NewFlagSet("", c)
+
+	if usage := f.Lookup("int").Usage; usage != "int_usage [$PROG_INT]" {
+		t.Fatalf("usage = %v", usage)
+	}
+}
+
+func TestCommander_ExecuteContext_SetsFlagsFromConfigFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"system":  "int = 1\nbool = true",
+		"project": "string = project\nint = 2",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := &clitest.SimpleFlagSetter{}
+	ct := &CommanderTest{
+		Commander: &Commander{
+			Command: &CommandStruct{
+				FlagSetter: fs,
+			},
+			EnvPrefix: "prog",
+			LookupEnv: func(key string) (string, bool) {
+				return "env", key == "PROG_STRING"
+			},
+			ConfigFiles: cli.ConfigFiles{
+				System:  filepath.Join(dir, "system"),
+				User:    filepath.Join(dir, "user"),
+				Project: filepath.Join(dir, "project"),
+			},
+		},
+		Args: strings.Fields("-bool=false"),
+	}
+
+	testCommanderTest(t, ct)
+
+	if !reflect.DeepEqual(fs, &clitest.SimpleFlagSetter{Int: 2, String: "env"}) {
+		t.Fatalf("flags = %v", fs)
+	}
+}
+
+func TestCommander_ExecuteContext_ConfigFileWithSectionIsError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte("[sub]"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Commander{
+		Name:        "command",
+		Command:     &CommandStruct{},
+		ConfigFiles: cli.ConfigFiles{User: path},
+	}
+
+	_, _, err = executeContext(c, nil, nil, nil)
+
+	want := &ParsingCommandError{&cli.ConfigError{Path: path, Line: 1, Err: cli.UnknownConfigSectionError("sub")}}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("err = %v WANT %v", err, want)
+	}
+}
+
+func TestCommander_ExecuteContext_OptionsFlagPrintsProvenanceTable(t *testing.T) {
+	ct := &CommanderTest{
+		Commander: &Commander{
+			Command: &CommandStruct{
+				FlagSetter:   &clitest.SimpleFlagSetter{},
+				ExecuteValue: clitest.NewExecuteFunc("executed", "", nil),
+			},
+			EnvPrefix: "prog",
+			LookupEnv: func(key string) (string, bool) {
+				return "env", key == "PROG_STRING"
+			},
+			OptionsFlag: "show-options",
+		},
+		Args: strings.Fields("-show-options -int 1"),
+		OutString: strings.Join([]string{
+			"OPTION         VALUE  SOURCE",
+			"-bool          false  default",
+			"-int           1      command line",
+			"-show-options  true   command line",
+			"-string        env    env PROG_STRING",
+			"",
+		}, "\n"),
+	}
+
+	testCommanderTest(t, ct)
+
+	if usage := cli.NewFlagSet("", ct.Commander).Lookup("show-options").Usage; usage != cli.OptionsFlagUsage+" [$PROG_SHOW_OPTIONS]" {
+		t.Fatalf("usage = %v", usage)
+	}
+}
+
+func TestCommander_ExecuteContext_OptionsFlagRecordsEnvOptsAsEnv(t *testing.T) {
+	env := map[string]string{"PROG_OPTS": "-int 1 -string opts"}
+	testCommanderTest(t, &CommanderTest{
+		Commander: &Commander{
+			Command: &CommandStruct{
+				FlagSetter:   &clitest.SimpleFlagSetter{},
+				ExecuteValue: clitest.NewExecuteFunc("executed", "", nil),
+			},
+			EnvPrefix: "prog",
+			EnvOpts:   true,
+			LookupEnv: func(key string) (string, bool) {
+				value, ok := env[key]
+				return value, ok
+			},
+			OptionsFlag: "show-options",
+		},
+		Args: strings.Fields("-show-options -string arg"),
+		OutString: strings.Join([]string{
+			"OPTION         VALUE  SOURCE",
+			"-bool          false  default",
+			"-int           1      env PROG_OPTS",
+			"-show-options  true   command line",
+			"-string        arg    command line",
+			"",
+		}, "\n"),
+	})
+}
+
+func TestCommander_ExecuteContext_ExpandsResponseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "args")
+	if err := ioutil.WriteFile(path, []byte("-int 1\n-string 'a b'\nparam\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		responseFiles bool
+		flags         *clitest.SimpleFlagSetter
+		params        []string
+	}{
+		{true, &clitest.SimpleFlagSetter{Int: 1, String: "a b", Bool: true}, []string{"param", "@at"}},
+		{false, &clitest.SimpleFlagSetter{Bool: true}, []string{"@" + path, "@@at"}},
+	}
+
+	for i, test := range tests {
+		fs := &clitest.SimpleFlagSetter{}
+		var params []string
+		testCommanderTest(t, &CommanderTest{
+			Commander: &Commander{
+				Command: &CommandStruct{
+					FlagSetter: fs,
+					ParameterSetter: &clitest.ParameterSetterStruct{
+						SetParametersValue: func(p []string) error {
+							params = p
+							return nil
+						},
+					},
+				},
+				ResponseFiles: test.responseFiles,
+			},
+			Args: []string{"@" + path, "-bool", "@@at"},
+		})
+
+		if !reflect.DeepEqual(fs, test.flags) || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%v: flags, params = %+v, %q WANT %+v, %q", i, fs, params, test.flags, test.params)
+		}
+	}
+
+	_, _, err = executeContext(&Commander{
+		Command:       &CommandStruct{},
+		ResponseFiles: true,
+	}, nil, []string{"@" + filepath.Join(dir, "missing")}, nil)
+	if pce, ok := err.(*ParsingCommandError); !ok {
+		t.Errorf("err = %#v", err)
+	} else if _, ok := pce.Err.(*cli.ResponseFileError); !ok {
+		t.Errorf("err = %#v", pce.Err)
+	}
+}
+
+func TestCommander_ExecuteContext_GNUOptions(t *testing.T) {
+	var verbose bool
+	var output string
+	var params []string
+	c := &Commander{
+		Name: "command",
+		Command: &CommandStruct{
+			FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				f.BoolVar(&verbose, "v", false, "verbose output")
+				f.BoolVar(&verbose, "verbose", false, "verbose output")
+				f.StringVar(&output, "o", "", "output `file`")
+				f.StringVar(&output, "output", "", "output `file`")
+			}),
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				SetParametersValue: func(p []string) error {
+					params = p
+					return nil
+				},
+			},
+		},
+		GNUOptions: true,
+	}
+
+	testCommanderTest(t, &CommanderTest{
+		Commander: c,
+		Args:      strings.Fields("one -vofile two --outp=other"),
+	})
+	if !verbose || output != "other" || !reflect.DeepEqual(params, []string{"one", "two"}) {
+		t.Fatalf("verbose, output, params = %v, %v, %q", verbose, output, params)
+	}
+
+	_, outErr, err := executeContext(c, nil, []string{"--help"}, nil)
+	if pce, ok := err.(*ParsingCommandError); !ok || pce.Err != flag.ErrHelp {
+		t.Fatalf("err = %v", err)
+	}
+	want := "\n" + OptionsName + ":\n" + strings.Join([]string{
+		"  -o, --output file",
+		"    \toutput file",
+		"  -v, --verbose",
+		"    \tverbose output",
+	}, "\n") + "\n"
+	if !strings.Contains(outErr.String(), want) {
+		t.Fatalf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, want)
+	}
+}
+
+func TestCommander_ExecuteContext_StopAtFirstParameter(t *testing.T) {
+	for _, gnu := range []bool{false, true} {
+		fs := &clitest.SimpleFlagSetter{}
+		var params []string
+		testCommanderTest(t, &CommanderTest{
+			Commander: &Commander{
+				Command: &CommandStruct{
+					FlagSetter: fs,
+					ParameterSetter: &clitest.ParameterSetterStruct{
+						SetParametersValue: func(p []string) error {
+							params = p
+							return nil
+						},
+					},
+					StopAtFirstParameterValue: true,
+				},
+				GNUOptions: gnu,
+			},
+			Args: strings.Fields(map[bool]string{false: "-bool", true: "--bool"}[gnu] + " cmd -int 1 -- arg"),
+		})
+
+		if !reflect.DeepEqual(fs, &clitest.SimpleFlagSetter{Bool: true}) {
+			t.Errorf("%v: flags = %+v", gnu, fs)
+		}
+		if want := strings.Fields("cmd -int 1 -- arg"); !reflect.DeepEqual(params, want) {
+			t.Errorf("%v: params = %q WANT %q", gnu, params, want)
+		}
+	}
+}
+
+type passthroughCommand struct {
+	*CommandStruct
+	passthrough []string
+}
+
+func (pc *passthroughCommand) SetPassthrough(args []string) error {
+	pc.passthrough = args
+	return nil
+}
+
+func TestCommander_ExecuteContext_SetsPassthrough(t *testing.T) {
+	tests := []struct {
+		args        string
+		params      []string
+		passthrough []string
+	}{
+		{"a b", []string{"a", "b"}, nil},
+		{"a -- -int 1 b", []string{"a"}, []string{"-int", "1", "b"}},
+		{"--", []string{}, []string{}},
+	}
+
+	for i, test := range tests {
+		for _, gnu := range []bool{false, true} {
+			var params []string
+			pc := &passthroughCommand{
+				CommandStruct: &CommandStruct{
+					FlagSetter: &clitest.SimpleFlagSetter{},
+					ParameterSetter: &clitest.ParameterSetterStruct{
+						SetParametersValue: func(p []string) error {
+							params = p
+							return nil
+						},
+					},
+				},
+			}
+			testCommanderTest(t, &CommanderTest{
+				Commander: &Commander{Command: pc, GNUOptions: gnu},
+				Args:      strings.Fields(test.args),
+			})
+
+			if !reflect.DeepEqual(params, test.params) || !reflect.DeepEqual(pc.passthrough, test.passthrough) {
+				t.Errorf("%v %v: params, passthrough = %q, %q WANT %q, %q", i, gnu, params, pc.passthrough, test.params, test.passthrough)
+			}
+		}
+	}
+}
+
+func TestCommander_ExecuteContext_NegativeNumbers(t *testing.T) {
+	fs := &clitest.SimpleFlagSetter{}
+	var params []string
+	c := &Commander{
+		Name: "command",
+		Command: &CommandStruct{
+			FlagSetter: fs,
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				SetParametersValue: func(p []string) error {
+					params = p
+					return nil
+				},
+			},
+		},
+		NegativeNumbers: true,
+	}
+
+	testCommanderTest(t, &CommanderTest{
+		Commander: c,
+		Args:      strings.Fields("-5 -int -2 -3.2"),
+	})
+	if fs.Int != -2 || !reflect.DeepEqual(params, []string{"-5", "-3.2"}) {
+		t.Fatalf("flags, params = %+v, %q", fs, params)
+	}
+
+	_, outErr, _ := executeContext(c, nil, []string{"-h"}, nil)
+	if !strings.Contains(outErr.String(), "\n"+cli.NegativeNumbersUsage+"\n") {
+		t.Fatalf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, cli.NegativeNumbersUsage)
+	}
+}
+
+func TestCommander_ExecuteContext_ReadsSecretsFromIn(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "pw")
+	if err := ioutil.WriteFile(path, []byte("file secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		password string
+	}{
+		{[]string{"-password", "-"}, "stdin secret"},
+		{[]string{"-password", cli.SecretFilePrefix + path}, "file secret"},
+		{[]string{"-password", "@file:" + path}, "file secret"},
+	}
+
+	for i, test := range tests {
+		password := ""
+		c := &Commander{
+			Command: &CommandStruct{
+				FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+					cli.SecretVar(f, &password, "password", "")
+				}),
+			},
+			ResponseFiles: true,
+		}
+
+		if _, _, err := executeContext(c, nil, test.args, strings.NewReader("stdin secret\n")); err != nil {
+			t.Fatalf("%v: %v", i, err)
+		}
+		if password != test.password {
+			t.Errorf("%v: password = %q WANT %q", i, password, test.password)
+		}
+	}
+}
@@ -19,5 +19,9 @@
 //
 //	{{.AvailableParameterUsageIfThereAreParameters}}
 //
+//A Command may instead be defined by a docopt style usage line with NewUsageCommand.
+//The usage line is printed as is in help and error output in place of
+//{{.Commander.Name}} {{.AvailableCommandLineArguments}}.
+//
 //Please see the examples for actual output.
 package command
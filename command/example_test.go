@@ -134,3 +134,35 @@ func Example_errorParsingArguments() {
 	//   -count int
 	//     	number of times to print parameters
 }
+
+func Example_usageCommand() {
+	command, err := NewUsageCommand("example_usage [-v] <src> <dst>...")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	command.FlagUsages = map[string]string{"v": "print verbose output"}
+	command.ExecuteValue = func(_ context.Context, args *UsageArguments, _ io.Reader, out, _ io.Writer) error {
+		fmt.Fprintln(out, args.Flags["v"], args.Parameters["src"], args.Parameters["dst"])
+		return nil
+	}
+
+	commander := &Commander{
+		Name:    "example_usage",
+		Command: command,
+	}
+
+	commander.ExecuteContext(context.Background(), strings.Fields("-v a b c"), os.Stdin, os.Stdout, os.Stdout)
+	commander.ExecuteContext(context.Background(), strings.Fields("a"), os.Stdin, os.Stdout, os.Stdout)
+
+	// Output:
+	// true [a] [b c]
+	// required parameter <DST...> not set
+	//
+	// usage: example_usage [-v] <src> <dst>...
+	//
+	// options:
+	//   -v	print verbose output
+	//
+	// parameters: <SRC> <DST...>
+}
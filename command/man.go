@@ -0,0 +1,30 @@
+package command
+
+import (
+	"path/filepath"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/man"
+)
+
+//ManPage returns a man page for c in the manual section.
+//
+//The page has NAME, SYNOPSIS, DESCRIPTION, OPTIONS, and PARAMETERS sections if
+//they are applicable to c. The SYNOPSIS is the same as the usage line in help
+//output.
+func (c *Commander) ManPage(section string) *man.Page {
+	name := filepath.Base(c.Name)
+	params, usage := c.ParameterUsage()
+
+	p := &man.Page{
+		Name:    name,
+		Section: section,
+	}
+	p.AddSection(man.Name, man.NameText(name, c.Description()))
+	p.AddSection(man.Synopsis, c.getCommandUsage(name))
+	p.AddSection(man.Description, c.Description())
+	p.AddSection(man.Options, "", man.NewFlagItems(cli.NewFlagSet(c.Name, c))...)
+	p.AddSection(man.Parameters, usage, man.NewParameterItems(params, FormatParameter)...)
+
+	return p
+}
@@ -0,0 +1,50 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestCommander_ManPage(t *testing.T) {
+	c := &Commander{
+		Name: "/usr/bin/prog",
+		Command: &CommandStruct{
+			DescriptionValue: "prog description",
+			FlagSetter:       clitest.NewStringsFlagSetter("foo"),
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				ParameterUsageValue: func() ([]*cli.Parameter, string) {
+					return []*cli.Parameter{{Name: "file", Many: true}}, "files to process"
+				},
+			},
+		},
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	if err := c.ManPage("1").Write(out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `.TH "PROG" "1" "" "" ""
+.SH "NAME"
+prog \- prog description
+.SH "SYNOPSIS"
+prog [[options | parameters]...]
+.SH "DESCRIPTION"
+prog description
+.SH "OPTIONS"
+.TP
+\fB\-foo\fR \fIstring\fR
+foo_usage (default foo_default)
+.SH "PARAMETERS"
+.TP
+\fB<FILE...>\fR
+.PP
+files to process
+`
+	if out.String() != want {
+		t.Fatalf("ManPage() = %v WANT %v", out, want)
+	}
+}
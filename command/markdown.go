@@ -0,0 +1,40 @@
+package command
+
+import (
+	"path/filepath"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/markdown"
+)
+
+//MarkdownPage returns a Markdown reference page for c.
+//
+//The page has the usage line, description, options, and parameters of c if
+//they are applicable. Options are listed in a table with their name, type,
+//default, and usage.
+func (c *Commander) MarkdownPage() *markdown.Page {
+	name := filepath.Base(c.Name)
+	params, usage := c.ParameterUsage()
+
+	p := &markdown.Page{Name: name}
+	p.AddHeading(1, name)
+	p.AddHeading(2, markdown.Synopsis)
+	p.AddCode(c.getCommandUsage(name))
+	if description := c.Description(); len(description) > 0 {
+		p.AddHeading(2, markdown.Description)
+		p.AddParagraph(description)
+	}
+	if f := cli.NewFlagSet(c.Name, c); cli.CountFlags(f) > 0 {
+		p.AddHeading(2, markdown.Options)
+		p.AddFlagTable(f)
+	}
+	if formatted := cli.FormatParameters(params, FormatParameter); len(formatted) > 0 || len(usage) > 0 {
+		p.AddHeading(2, markdown.Parameters)
+		if len(formatted) > 0 {
+			p.AddParagraph(markdown.Code(formatted))
+		}
+		p.AddParagraph(usage)
+	}
+
+	return p
+}
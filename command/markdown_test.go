@@ -0,0 +1,42 @@
+package command
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestCommander_MarkdownPage(t *testing.T) {
+	c := &Commander{
+		Name: "/usr/bin/prog",
+		Command: &CommandStruct{
+			DescriptionValue: "prog description",
+			FlagSetter:       clitest.NewStringsFlagSetter("foo"),
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				ParameterUsageValue: func() ([]*cli.Parameter, string) {
+					return []*cli.Parameter{{Name: "file", Many: true}}, "files to process"
+				},
+			},
+		},
+	}
+
+	p := c.MarkdownPage()
+	out := bytes.NewBuffer([]byte{})
+	if err := p.Write(out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# prog\n\n" +
+		"## Synopsis\n\n```\nprog [[options | parameters]...]\n```\n\n" +
+		"## Description\n\nprog description\n\n" +
+		"## Options\n\n| Name | Type | Default | Usage |\n| --- | --- | --- | --- |\n| `-foo` | string | `foo_default` | foo_usage |\n\n" +
+		"## Parameters\n\n`<FILE...>`\n\nfiles to process\n"
+	if p.FileName() != "prog.md" {
+		t.Errorf("FileName() = %v WANT %v", p.FileName(), "prog.md")
+	}
+	if out.String() != want {
+		t.Fatalf("MarkdownPage() = %v WANT %v", out, want)
+	}
+}
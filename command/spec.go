@@ -0,0 +1,22 @@
+package command
+
+import (
+	"path/filepath"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/spec"
+)
+
+//Spec returns a machine-readable description of c's name, description, flags,
+//and parameters.
+func (c *Commander) Spec() *spec.Command {
+	params, usage := c.ParameterUsage()
+
+	return &spec.Command{
+		Name:           filepath.Base(c.Name),
+		Description:    c.Description(),
+		Flags:          spec.NewFlags(cli.NewFlagSet(c.Name, c)),
+		Parameters:     spec.NewParameters(params),
+		ParameterUsage: usage,
+	}
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+	"github.com/gogolfing/cli/spec"
+)
+
+func TestCommander_Spec(t *testing.T) {
+	c := &Commander{
+		Name: "/usr/bin/prog",
+		Command: &CommandStruct{
+			DescriptionValue: "prog description",
+			FlagSetter:       clitest.NewStringsFlagSetter("foo"),
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				ParameterUsageValue: func() ([]*cli.Parameter, string) {
+					return []*cli.Parameter{{Name: "file", Optional: true, Many: true}}, "files to process"
+				},
+			},
+		},
+	}
+
+	result := c.Spec()
+
+	want := &spec.Command{
+		Name:           "prog",
+		Description:    "prog description",
+		Flags:          []*spec.Flag{{Name: "foo", Type: "string", Default: "foo_default", Usage: "foo_usage"}},
+		Parameters:     []*spec.Parameter{{Name: "file", Optional: true, Many: true}},
+		ParameterUsage: "files to process",
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Fatalf("Spec() = %v WANT %v", result, want)
+	}
+}
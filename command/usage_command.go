@@ -0,0 +1,336 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//UsageCommand is a Command whose flags and parameters are defined by a docopt
+//style usage line, e.g.
+//	prog [-v] [-o <file>] <src> <dst>...
+//
+//UsageCommand implements UsageLiner, so help and error output contain the usage
+//line exactly as it was supplied to NewUsageCommand.
+type UsageCommand struct {
+	//DescriptionValue is returned from Command's Description() method.
+	DescriptionValue string
+
+	//FlagUsages are the usages of flags in help output by flag name.
+	FlagUsages map[string]string
+
+	//ExecuteValue is called from Execute with the parsed UsageArguments if not nil.
+	ExecuteValue func(ctx context.Context, args *UsageArguments, in io.Reader, out, outErr io.Writer) error
+
+	usageLine string
+	flags     []*usageFlag
+	params    []*cli.Parameter
+
+	flagValues  map[string]interface{}
+	paramValues map[string][]string
+}
+
+//UsageArguments are the values parsed for a UsageCommand.
+type UsageArguments struct {
+	//Flags are the flag values by name without the leading "-".
+	//Values are bool for flags without a value and string otherwise.
+	Flags map[string]interface{}
+
+	//Parameters are the parameter values by name without "<" and ">".
+	//Optional parameters that are not supplied are not present.
+	Parameters map[string][]string
+}
+
+type usageFlag struct {
+	name      string
+	valueName string
+}
+
+//UsageError is returned from NewUsageCommand if the usage line cannot be parsed.
+type UsageError struct {
+	//Element is the part of the usage line that cannot be parsed.
+	Element string
+
+	//Reason describes why Element cannot be parsed.
+	Reason string
+}
+
+//Error is the error implementation for e.
+func (e *UsageError) Error() string {
+	return fmt.Sprintf("invalid usage element %q: %s", e.Element, e.Reason)
+}
+
+//NewUsageCommand returns a UsageCommand defined by usage.
+//A leading "usage:" (ignoring case) and surrounding whitespace are removed from
+//usage. The first word is the program name and is otherwise ignored.
+//
+//The remaining elements may be:
+//	[-v]            a boolean flag named "v"
+//	[--verbose]     a boolean flag named "verbose"
+//	[-o <file>]     a string flag named "o"
+//	[-o=<file>]     a string flag named "o"
+//	<src>           a required parameter named "src"
+//	[<src>]         an optional parameter
+//	<dst>...        a parameter with many values (also [<dst>...] and [<dst>]...)
+//	[--]            documents that "--" may be used and is otherwise ignored
+//
+//Flags must be in brackets because flags are always optional.
+//Only the last parameter may have many values and required parameters may not
+//follow optional ones.
+//The returned error, if not nil, is a *UsageError.
+func NewUsageCommand(usage string) (*UsageCommand, error) {
+	usageLine := strings.TrimSpace(usage)
+	if strings.HasPrefix(strings.ToLower(usageLine), Usage) {
+		usageLine = strings.TrimSpace(usageLine[len(Usage):])
+	}
+	if strings.ContainsAny(usageLine, "\r\n") {
+		return nil, &UsageError{usageLine, "usage must be a single line"}
+	}
+
+	uc := &UsageCommand{usageLine: usageLine}
+
+	fields := strings.Fields(usageLine)
+	if len(fields) == 0 {
+		return nil, &UsageError{usageLine, "missing program name"}
+	}
+	for i := 1; i < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], "[") {
+			if err := uc.addElement(fields[i:i+1], false, false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		group := []string{fields[i][1:]}
+		for !strings.Contains(group[len(group)-1], "]") {
+			i++
+			if i >= len(fields) {
+				return nil, &UsageError{strings.Join(group, " "), "missing ]"}
+			}
+			group = append(group, fields[i])
+		}
+
+		if strings.Contains(strings.Join(group, ""), "[") {
+			return nil, &UsageError{"[" + strings.Join(group, " "), "brackets may not be nested"}
+		}
+
+		last := group[len(group)-1]
+		end := strings.Index(last, "]")
+		suffix := last[end+1:]
+		group[len(group)-1] = last[:end]
+		if suffix != "" && suffix != "..." {
+			return nil, &UsageError{fields[i], "unexpected characters after ]"}
+		}
+
+		if err := uc.addElement(group, true, suffix == "..."); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.validateParameters(); err != nil {
+		return nil, err
+	}
+
+	uc.flagValues = map[string]interface{}{}
+	for _, fl := range uc.flags {
+		if len(fl.valueName) == 0 {
+			uc.flagValues[fl.name] = new(bool)
+		} else {
+			uc.flagValues[fl.name] = new(string)
+		}
+	}
+	return uc, nil
+}
+
+func (uc *UsageCommand) addElement(tokens []string, optional, many bool) error {
+	element := strings.Join(tokens, " ")
+	if optional {
+		element = "[" + element + "]"
+	}
+	if strings.ContainsAny(strings.Join(tokens, ""), "[]") {
+		return &UsageError{element, "brackets must be around whole elements"}
+	}
+	if len(tokens) == 0 || len(tokens[0]) == 0 {
+		return &UsageError{element, "empty element"}
+	}
+
+	if tokens[0] == cli.DoubleMinus && len(tokens) == 1 && optional && !many {
+		return nil
+	}
+	if strings.HasPrefix(tokens[0], "-") {
+		if !optional {
+			return &UsageError{element, "flags must be in brackets"}
+		}
+		if many {
+			return &UsageError{element, "flags may not have many values"}
+		}
+		return uc.addFlag(element, tokens)
+	}
+
+	if len(tokens) != 1 {
+		return &UsageError{element, "parameters must be alone in brackets"}
+	}
+	name := tokens[0]
+	if strings.HasSuffix(name, "...") {
+		name, many = strings.TrimSuffix(name, "..."), true
+	}
+	if !isUsageName(name) {
+		return &UsageError{element, "parameters must be of the form <name>"}
+	}
+
+	uc.params = append(uc.params, &cli.Parameter{
+		Name:     name[1 : len(name)-1],
+		Optional: optional,
+		Many:     many,
+	})
+	return nil
+}
+
+func (uc *UsageCommand) addFlag(element string, tokens []string) error {
+	name := strings.TrimLeft(tokens[0], "-")
+	minuses := len(tokens[0]) - len(name)
+	valueName := ""
+	if i := strings.Index(name, "="); i >= 0 {
+		name, valueName = name[:i], name[i+1:]
+		if len(tokens) != 1 || !isUsageName(valueName) {
+			return &UsageError{element, "flag values must be of the form <name>"}
+		}
+	} else if len(tokens) == 2 {
+		valueName = tokens[1]
+		if !isUsageName(valueName) {
+			return &UsageError{element, "flag values must be of the form <name>"}
+		}
+	} else if len(tokens) > 2 {
+		return &UsageError{element, "too many words in brackets"}
+	}
+
+	if len(name) == 0 || minuses > 2 {
+		return &UsageError{element, "invalid flag name"}
+	}
+	for _, fl := range uc.flags {
+		if fl.name == name {
+			return &UsageError{element, "duplicate flag"}
+		}
+	}
+
+	if len(valueName) > 0 {
+		valueName = valueName[1 : len(valueName)-1]
+	}
+	uc.flags = append(uc.flags, &usageFlag{name: name, valueName: valueName})
+	return nil
+}
+
+func (uc *UsageCommand) validateParameters() error {
+	names := map[string]bool{}
+	optional := false
+	for i, param := range uc.params {
+		element := FormatParameter(param)
+		if names[param.Name] {
+			return &UsageError{element, "duplicate parameter"}
+		}
+		names[param.Name] = true
+		if param.Many && i != len(uc.params)-1 {
+			return &UsageError{element, "only the last parameter may have many values"}
+		}
+		if optional && !param.Optional {
+			return &UsageError{element, "required parameters may not follow optional parameters"}
+		}
+		optional = optional || param.Optional
+	}
+	return nil
+}
+
+//isUsageName returns whether or not value is of the form <name>.
+func isUsageName(value string) bool {
+	return len(value) > 2 && strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") &&
+		!strings.ContainsAny(value[1:len(value)-1], "<>")
+}
+
+//UsageLine returns the usage line supplied to NewUsageCommand without a leading
+//"usage:".
+func (uc *UsageCommand) UsageLine() string {
+	return uc.usageLine
+}
+
+//Description returns uc.DescriptionValue.
+func (uc *UsageCommand) Description() string {
+	return uc.DescriptionValue
+}
+
+//SetFlags defines the flags from the usage line in f.
+//Flags with values are string flags and all others are bool flags.
+//Their usages are from uc.FlagUsages.
+//The flags of every f share the values allocated by NewUsageCommand.
+func (uc *UsageCommand) SetFlags(f *flag.FlagSet) {
+	for _, fl := range uc.flags {
+		switch value := uc.flagValues[fl.name].(type) {
+		case *bool:
+			f.BoolVar(value, fl.name, false, uc.FlagUsages[fl.name])
+		case *string:
+			f.StringVar(value, fl.name, "", uc.FlagUsages[fl.name])
+		}
+	}
+}
+
+//ParameterUsage returns the parameters from the usage line and the empty string.
+func (uc *UsageCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return uc.params, ""
+}
+
+//SetParameters assigns params to the parameters from the usage line in order.
+//A parameter with many values receives all remaining params.
+func (uc *UsageCommand) SetParameters(params []string) error {
+	values := map[string][]string{}
+	for i, param := range uc.params {
+		switch {
+		case i >= len(params):
+			if !param.Optional {
+				return &cli.RequiredParameterNotSetError{
+					Name:      param.Name,
+					Many:      param.Many,
+					Formatted: FormatParameter(param),
+				}
+			}
+		case param.Many:
+			values[param.Name] = params[i:]
+		default:
+			values[param.Name] = params[i : i+1]
+		}
+	}
+
+	n := len(uc.params)
+	if len(params) > n && (n == 0 || !uc.params[n-1].Many) {
+		return cli.ErrTooManyParameters
+	}
+
+	uc.paramValues = values
+	return nil
+}
+
+//Execute calls and returns the result from uc.ExecuteValue with the parsed
+//UsageArguments if the field is not nil.
+//Otherwise, it returns nil.
+func (uc *UsageCommand) Execute(ctx context.Context, in io.Reader, out, outErr io.Writer) error {
+	if uc.ExecuteValue == nil {
+		return nil
+	}
+
+	args := &UsageArguments{
+		Flags:      map[string]interface{}{},
+		Parameters: uc.paramValues,
+	}
+	for name, value := range uc.flagValues {
+		switch value := value.(type) {
+		case *bool:
+			args.Flags[name] = *value
+		case *string:
+			args.Flags[name] = *value
+		}
+	}
+
+	return uc.ExecuteValue(ctx, args, in, out, outErr)
+}
@@ -0,0 +1,220 @@
+package command
+
+import (
+	"context"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+)
+
+func TestNewUsageCommand(t *testing.T) {
+	tests := []struct {
+		usage     string
+		usageLine string
+		flags     []*usageFlag
+		params    []*cli.Parameter
+	}{
+		{
+			"prog",
+			"prog",
+			nil,
+			nil,
+		},
+		{
+			"  Usage:  prog [-v] <src> <dst>...  ",
+			"prog [-v] <src> <dst>...",
+			[]*usageFlag{{name: "v"}},
+			[]*cli.Parameter{{Name: "src"}, {Name: "dst", Many: true}},
+		},
+		{
+			"prog [--verbose] [-o <file>] [--count=<n>] [--] [<a>] [<b>...]",
+			"prog [--verbose] [-o <file>] [--count=<n>] [--] [<a>] [<b>...]",
+			[]*usageFlag{{name: "verbose"}, {name: "o", valueName: "file"}, {name: "count", valueName: "n"}},
+			[]*cli.Parameter{{Name: "a", Optional: true}, {Name: "b", Optional: true, Many: true}},
+		},
+		{
+			"prog [<files>]...",
+			"prog [<files>]...",
+			nil,
+			[]*cli.Parameter{{Name: "files", Optional: true, Many: true}},
+		},
+	}
+
+	for i, test := range tests {
+		uc, err := NewUsageCommand(test.usage)
+		if err != nil {
+			t.Errorf("%v: NewUsageCommand() error = %v", i, err)
+			continue
+		}
+
+		if uc.UsageLine() != test.usageLine {
+			t.Errorf("%v: UsageLine() = %v WANT %v", i, uc.UsageLine(), test.usageLine)
+		}
+		if !reflect.DeepEqual(uc.flags, test.flags) {
+			t.Errorf("%v: flags = %v WANT %v", i, uc.flags, test.flags)
+		}
+		if params, _ := uc.ParameterUsage(); !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%v: params = %v WANT %v", i, params, test.params)
+		}
+	}
+}
+
+func TestNewUsageCommand_Errors(t *testing.T) {
+	tests := []struct {
+		usage string
+		err   *UsageError
+	}{
+		{"", &UsageError{"", "missing program name"}},
+		{"prog\n<a>", &UsageError{"prog\n<a>", "usage must be a single line"}},
+		{"prog [-v", &UsageError{"-v", "missing ]"}},
+		{"prog [-v]x", &UsageError{"[-v]x", "unexpected characters after ]"}},
+		{"prog [[-v]]", &UsageError{"[[-v]]", "brackets may not be nested"}},
+		{"prog <a>]", &UsageError{"<a>]", "brackets must be around whole elements"}},
+		{"prog []", &UsageError{"[]", "empty element"}},
+		{"prog -v", &UsageError{"-v", "flags must be in brackets"}},
+		{"prog [-v]...", &UsageError{"[-v]", "flags may not have many values"}},
+		{"prog [-o file]", &UsageError{"[-o file]", "flag values must be of the form <name>"}},
+		{"prog [-o=file]", &UsageError{"[-o=file]", "flag values must be of the form <name>"}},
+		{"prog [-o <a> <b>]", &UsageError{"[-o <a> <b>]", "too many words in brackets"}},
+		{"prog [---o]", &UsageError{"[---o]", "invalid flag name"}},
+		{"prog [-v] [--v]", &UsageError{"[--v]", "duplicate flag"}},
+		{"prog [<a> <b>]", &UsageError{"[<a> <b>]", "parameters must be alone in brackets"}},
+		{"prog add", &UsageError{"add", "parameters must be of the form <name>"}},
+		{"prog <a> <a>", &UsageError{"<A>", "duplicate parameter"}},
+		{"prog <a>... <b>", &UsageError{"<A...>", "only the last parameter may have many values"}},
+		{"prog [<a>] <b>", &UsageError{"<B>", "required parameters may not follow optional parameters"}},
+	}
+
+	for i, test := range tests {
+		_, err := NewUsageCommand(test.usage)
+
+		if !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: NewUsageCommand() error = %v WANT %v", i, err, test.err)
+		}
+	}
+}
+
+func TestUsageError_Error(t *testing.T) {
+	err := &UsageError{Element: "-v", Reason: "flags must be in brackets"}
+
+	if result := err.Error(); result != `invalid usage element "-v": flags must be in brackets` {
+		t.Fatal(result)
+	}
+}
+
+func TestUsageCommand_ExecutesWithArguments(t *testing.T) {
+	uc, err := NewUsageCommand("prog [-v] [-o <file>] <src> <dst>...")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var args *UsageArguments
+	uc.ExecuteValue = func(_ context.Context, a *UsageArguments, _ io.Reader, _, _ io.Writer) error {
+		args = a
+		return nil
+	}
+
+	ct := &CommanderTest{
+		Commander: &Commander{Command: uc},
+		Args:      strings.Fields("a -v b -o out c"),
+	}
+	testCommanderTest(t, ct)
+
+	want := &UsageArguments{
+		Flags: map[string]interface{}{"v": true, "o": "out"},
+		Parameters: map[string][]string{
+			"src": {"a"},
+			"dst": {"b", "c"},
+		},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("UsageArguments = %v WANT %v", args, want)
+	}
+}
+
+func TestUsageCommand_SetFlags_SharesValues(t *testing.T) {
+	uc, err := NewUsageCommand("prog [-v] [-o <file>]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var args *UsageArguments
+	uc.ExecuteValue = func(_ context.Context, a *UsageArguments, _ io.Reader, _, _ io.Writer) error {
+		args = a
+		return nil
+	}
+
+	f := cli.NewFlagSet("", uc)
+	cli.NewFlagSet("", uc)
+	if err := f.Parse(strings.Fields("-v -o out")); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.Execute(nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]interface{}{"v": true, "o": "out"}; !reflect.DeepEqual(args.Flags, want) {
+		t.Fatalf("Flags = %v WANT %v", args.Flags, want)
+	}
+}
+
+func TestUsageCommand_SetParameters(t *testing.T) {
+	tests := []struct {
+		usage  string
+		params []string
+		values map[string][]string
+		err    error
+	}{
+		{"prog", nil, map[string][]string{}, nil},
+		{"prog", []string{"a"}, nil, cli.ErrTooManyParameters},
+		{"prog <a> [<b>]", []string{"1"}, map[string][]string{"a": {"1"}}, nil},
+		{"prog <a> [<b>]", []string{"1", "2", "3"}, nil, cli.ErrTooManyParameters},
+		{
+			"prog <a> <b>...",
+			[]string{"1"},
+			nil,
+			&cli.RequiredParameterNotSetError{Name: "b", Many: true, Formatted: "<B...>"},
+		},
+	}
+
+	for i, test := range tests {
+		uc, err := NewUsageCommand(test.usage)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = uc.SetParameters(test.params)
+
+		if !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: SetParameters() = %v WANT %v", i, err, test.err)
+		}
+		if !reflect.DeepEqual(uc.paramValues, test.values) {
+			t.Errorf("%v: values = %v WANT %v", i, uc.paramValues, test.values)
+		}
+	}
+}
+
+func TestUsageCommand_HelpOutputRoundTripsUsageLine(t *testing.T) {
+	uc, err := NewUsageCommand("usage: prog [-v] [-o <file>] <src> <dst>...")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc.DescriptionValue = "copies files"
+	uc.FlagUsages = map[string]string{"o": "output `file`"}
+
+	ct := &CommanderTest{
+		Commander: &Commander{Name: "command", Command: uc},
+		Args:      []string{"-help"},
+		OutErrString: "command - copies files" + "\n\n" +
+			Usage + " prog [-v] [-o <file>] <src> <dst>..." + "\n\n" +
+			OptionsName + ":\n" +
+			"  -o file\n    \toutput file\n  -v\t" + "\n\n" +
+			ParametersName + ": <SRC> <DST...>" + "\n",
+		Err: &ParsingCommandError{flag.ErrHelp},
+	}
+
+	testCommanderTest(t, ct)
+}
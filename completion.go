@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+)
+
+//Completion describes a word being completed on the command line and its position
+//within the arguments that precede it.
+type Completion struct {
+	//Args are the arguments that precede Word.
+	Args []string
+
+	//Word is the partial word being completed.
+	//If the word on the command line is of the form -flag=value, then Word is
+	//only the value.
+	Word string
+
+	//Flag is the name of the flag whose value is being completed.
+	//It is the empty string if a parameter is being completed.
+	Flag string
+
+	//Parameters are the parameters present in Args as returned from
+	//ParseArguments, including any arguments after "--".
+	//If Flag is empty, then Word is the parameter at index len(Parameters).
+	Parameters []string
+}
+
+//Completer is an optional interface that commands may implement in order to
+//complete flag and parameter values on the command line.
+type Completer interface {
+	//Complete returns candidate values for c.Word.
+	//Candidates not beginning with c.Word are ignored by callers, so implementations
+	//may return all possible values.
+	Complete(c *Completion) []string
+}
+
+//NewCompletion returns the Completion of word that follows args given the flags
+//defined in f and parsed with the parser selected by opts, so that completion
+//agrees with how the arguments are parsed when executed.
+//ParseArguments is used to determine the Parameters in args.
+//
+//With opts.GNU, options are named as in ParseArgumentsGNU, e.g. "-o", "--output",
+//and "-vo", and word may be an option with its value, e.g. "--output=js" or "-ojs".
+//With opts.StopAtFirstParameter, every word after the first parameter is a
+//parameter. With opts.NegativeNumbers, a word that is a negative number is
+//a parameter unless it names a flag.
+//
+//The returned value is nil if word is a flag name, rather than a flag or parameter
+//value, or if args cannot be parsed.
+func NewCompletion(f *flag.FlagSet, args []string, word string, opts ParseOptions) *Completion {
+	opts.Passthrough = false
+	c := &Completion{
+		Args: args,
+		Word: word,
+	}
+
+	parseArgs := args
+	isFlagName := false
+	if !containsDoubleMinus(args) {
+		if strings.HasPrefix(word, "-") && !isNegativeNumberParameter(f, word, opts) {
+			name, value, ok := getFlagValue(f, word, opts.GNU)
+			c.Flag, c.Word, isFlagName = name, value, !ok
+		} else if n := len(args); n > 0 {
+			if name, ok := getValueFlagName(f, args[n-1], opts.GNU); ok {
+				c.Flag = name
+				parseArgs = args[:n-1]
+			}
+		}
+	}
+
+	params, _, err := ParseArguments(f, parseArgs, opts)
+	if err != nil {
+		return nil
+	}
+	if opts.StopAtFirstParameter && len(params) > 0 {
+		//Every argument after the first parameter, including word, is a parameter.
+		params = append(params, args[len(parseArgs):]...)
+		c.Flag, c.Word, isFlagName = "", word, false
+	}
+	if isFlagName {
+		return nil
+	}
+	c.Parameters = params
+
+	return c
+}
+
+//Complete calls completer with the Completion of word following args, given
+//the flags defined in f and opts, and returns the candidates that begin with
+//the word being completed.
+//If word is a flag with its value, e.g. -flag=value or -fvalue, then the returned
+//candidates include the flag prefix, e.g. -flag= or -f.
+func Complete(completer Completer, f *flag.FlagSet, args []string, word string, opts ParseOptions) []string {
+	c := NewCompletion(f, args, word, opts)
+	if c == nil {
+		return nil
+	}
+
+	prefix := strings.TrimSuffix(word, c.Word)
+	result := []string{}
+	for _, candidate := range completer.Complete(c) {
+		if strings.HasPrefix(candidate, c.Word) {
+			result = append(result, prefix+candidate)
+		}
+	}
+	return result
+}
+
+//IsBoolFlag returns whether or not fl is a boolean flag that does not require
+//a value, as determined by the flag package.
+func IsBoolFlag(fl *flag.Flag) bool {
+	bf, ok := fl.Value.(interface {
+		IsBoolFlag() bool
+	})
+	return ok && bf.IsBoolFlag()
+}
+
+func containsDoubleMinus(args []string) bool {
+	for _, arg := range args {
+		if arg == DoubleMinus {
+			return true
+		}
+	}
+	return false
+}
+
+//isNegativeNumberParameter returns whether or not arg is a negative number that
+//is a parameter when parsing with opts.
+func isNegativeNumberParameter(f *flag.FlagSet, arg string, opts ParseOptions) bool {
+	if !opts.NegativeNumbers || !isNegativeNumber(arg) {
+		return false
+	}
+	if opts.GNU {
+		return f.Lookup(arg[1:2]) == nil
+	}
+	return f.Lookup(arg[1:]) == nil
+}
+
+//getFlagValue returns the name of the flag in f and its value given in arg, which
+//is of the form -name=value or --name=value, or, if gnu is true, --name=value or
+//clustered short options whose last takes a value, e.g. -vovalue.
+func getFlagValue(f *flag.FlagSet, arg string, gnu bool) (name, value string, ok bool) {
+	if !gnu {
+		name, value, ok = splitFlagValue(arg)
+		return name, value, ok && f.Lookup(name) != nil
+	}
+
+	if strings.HasPrefix(arg, DoubleMinus) {
+		name, value, ok = splitFlagValue(arg)
+		if !ok {
+			return "", "", false
+		}
+		fl, err := lookupGNULong(f, name)
+		if err != nil {
+			return "", "", false
+		}
+		return fl.Name, value, true
+	}
+
+	fl, value := getGNUShortValueFlag(f, arg)
+	if fl == nil || len(value) == 0 {
+		return "", "", false
+	}
+	return fl.Name, value, true
+}
+
+//splitFlagValue splits arg of the form -name=value or --name=value.
+func splitFlagValue(arg string) (name, value string, ok bool) {
+	name = trimFlagMinuses(arg)
+	if i := strings.Index(name, "="); i > 0 {
+		return name[:i], name[i+1:], true
+	}
+	return "", "", false
+}
+
+//getGNUShortValueFlag returns the first flag of the clustered short options in
+//arg that takes a value and the value given with it in arg, if any.
+//It returns a nil flag if arg names no such flag.
+func getGNUShortValueFlag(f *flag.FlagSet, arg string) (*flag.Flag, string) {
+	if len(arg) < 2 || arg[0] != '-' || arg[1] == '-' {
+		return nil, ""
+	}
+	arg = arg[1:]
+	for i, r := range arg {
+		fl := f.Lookup(string(r))
+		if fl == nil {
+			return nil, ""
+		}
+		if !IsBoolFlag(fl) {
+			return fl, arg[i+len(string(r)):]
+		}
+	}
+	return nil, ""
+}
+
+//getValueFlagName returns the name of the flag in f that arg names if that flag
+//requires a following value argument. If gnu is true, then arg may be clustered
+//short options whose last requires a value or an unambiguous prefix of a long
+//option.
+func getValueFlagName(f *flag.FlagSet, arg string, gnu bool) (string, bool) {
+	if !strings.HasPrefix(arg, "-") || arg == DoubleMinus || strings.Contains(arg, "=") {
+		return "", false
+	}
+
+	var fl *flag.Flag
+	switch {
+	case !gnu:
+		fl = f.Lookup(trimFlagMinuses(arg))
+	case strings.HasPrefix(arg, DoubleMinus):
+		fl, _ = lookupGNULong(f, arg[2:])
+	default:
+		var value string
+		if fl, value = getGNUShortValueFlag(f, arg); len(value) > 0 {
+			return "", false
+		}
+	}
+	if fl == nil || IsBoolFlag(fl) || IsOptionalValueFlag(fl) {
+		return "", false
+	}
+	return fl.Name, true
+}
+
+func trimFlagMinuses(arg string) string {
+	if strings.HasPrefix(arg, DoubleMinus) {
+		return arg[2:]
+	}
+	return strings.TrimPrefix(arg, "-")
+}
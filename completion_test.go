@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewCompletion(t *testing.T) {
+	newFlagSetWithFlags := func() *flag.FlagSet {
+		f := newFlagSet("")
+		f.String("format", "", "")
+		f.Bool("v", false, "")
+		OptionalStringVar(f, new(string), "color", "always", "")
+		return f
+	}
+
+	tests := []struct {
+		args       []string
+		word       string
+		completion *Completion
+	}{
+		{
+			nil,
+			"",
+			&Completion{Word: "", Parameters: []string{}},
+		},
+		{
+			strings.Fields("a -v b"),
+			"c",
+			&Completion{Args: strings.Fields("a -v b"), Word: "c", Parameters: []string{"a", "b"}},
+		},
+		{
+			strings.Fields("a -format"),
+			"js",
+			&Completion{Args: strings.Fields("a -format"), Word: "js", Flag: "format", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("a --format"),
+			"",
+			&Completion{Args: strings.Fields("a --format"), Word: "", Flag: "format", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("a"),
+			"-format=js",
+			&Completion{Args: strings.Fields("a"), Word: "js", Flag: "format", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("-v"),
+			"a",
+			&Completion{Args: strings.Fields("-v"), Word: "a", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-color"),
+			"a",
+			&Completion{Args: strings.Fields("-color"), Word: "a", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-- -format"),
+			"-a",
+			&Completion{Args: strings.Fields("-- -format"), Word: "-a", Parameters: []string{"-format"}},
+		},
+		{
+			nil,
+			"-form",
+			nil,
+		},
+		{
+			nil,
+			"-other=value",
+			nil,
+		},
+		{
+			strings.Fields("-other"),
+			"a",
+			nil,
+		},
+	}
+
+	for i, test := range tests {
+		c := NewCompletion(newFlagSetWithFlags(), test.args, test.word, ParseOptions{})
+
+		if !reflect.DeepEqual(c, test.completion) {
+			t.Errorf("%v: NewCompletion() = %+v WANT %+v", i, c, test.completion)
+		}
+	}
+}
+
+func TestNewCompletion_ParseOptions(t *testing.T) {
+	newFlagSetWithFlags := func() *flag.FlagSet {
+		f := newFlagSet("")
+		output := ""
+		f.StringVar(&output, "o", "", "")
+		f.StringVar(&output, "output", "", "")
+		verbose := false
+		f.BoolVar(&verbose, "v", false, "")
+		f.BoolVar(&verbose, "verbose", false, "")
+		f.Int("1", 0, "")
+		return f
+	}
+	gnu := ParseOptions{GNU: true}
+	stop := ParseOptions{StopAtFirstParameter: true}
+	numbers := ParseOptions{NegativeNumbers: true}
+
+	tests := []struct {
+		args       []string
+		word       string
+		opts       ParseOptions
+		completion *Completion
+	}{
+		{
+			strings.Fields("a -vo"),
+			"",
+			gnu,
+			&Completion{Args: strings.Fields("a -vo"), Word: "", Flag: "o", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("--out"),
+			"js",
+			gnu,
+			&Completion{Args: strings.Fields("--out"), Word: "js", Flag: "output", Parameters: []string{}},
+		},
+		{
+			nil,
+			"-vojs",
+			gnu,
+			&Completion{Word: "js", Flag: "o", Parameters: []string{}},
+		},
+		{
+			nil,
+			"--outp=js",
+			gnu,
+			&Completion{Word: "js", Flag: "output", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-vojs"),
+			"a",
+			gnu,
+			&Completion{Args: strings.Fields("-vojs"), Word: "a", Parameters: []string{}},
+		},
+		{
+			nil,
+			"-vo",
+			gnu,
+			nil,
+		},
+		{
+			nil,
+			"--verb",
+			gnu,
+			nil,
+		},
+		{
+			strings.Fields("-output"),
+			"",
+			gnu,
+			&Completion{Args: strings.Fields("-output"), Word: "", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-o"),
+			"js",
+			stop,
+			&Completion{Args: strings.Fields("-o"), Word: "js", Flag: "o", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-v a -o"),
+			"js",
+			stop,
+			&Completion{Args: strings.Fields("-v a -o"), Word: "js", Parameters: []string{"a", "-o"}},
+		},
+		{
+			strings.Fields("a"),
+			"-o=js",
+			stop,
+			&Completion{Args: strings.Fields("a"), Word: "-o=js", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("a"),
+			"-x",
+			stop,
+			&Completion{Args: strings.Fields("a"), Word: "-x", Parameters: []string{"a"}},
+		},
+		{
+			strings.Fields("-5"),
+			"-2",
+			numbers,
+			&Completion{Args: strings.Fields("-5"), Word: "-2", Parameters: []string{"-5"}},
+		},
+		{
+			nil,
+			"-5",
+			ParseOptions{},
+			nil,
+		},
+		{
+			nil,
+			"-1=2",
+			numbers,
+			&Completion{Word: "2", Flag: "1", Parameters: []string{}},
+		},
+		{
+			strings.Fields("-15"),
+			"a",
+			ParseOptions{GNU: true, NegativeNumbers: true},
+			&Completion{Args: strings.Fields("-15"), Word: "a", Parameters: []string{}},
+		},
+	}
+
+	for i, test := range tests {
+		c := NewCompletion(newFlagSetWithFlags(), test.args, test.word, test.opts)
+
+		if !reflect.DeepEqual(c, test.completion) {
+			t.Errorf("%v: NewCompletion() = %+v WANT %+v", i, c, test.completion)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	f := newFlagSet("")
+	f.String("format", "", "")
+
+	completer := CompleterFunc(func(c *Completion) []string {
+		if c.Flag == "format" {
+			return []string{"json", "text"}
+		}
+		return []string{"origin", "upstream"}
+	})
+
+	tests := []struct {
+		args   []string
+		word   string
+		result []string
+	}{
+		{nil, "", []string{"origin", "upstream"}},
+		{nil, "up", []string{"upstream"}},
+		{strings.Fields("-format"), "", []string{"json", "text"}},
+		{nil, "-format=t", []string{"-format=text"}},
+		{nil, "-f", nil},
+	}
+
+	for i, test := range tests {
+		result := Complete(completer, f, test.args, test.word, ParseOptions{})
+
+		if (len(result) != 0 || len(test.result) != 0) && !reflect.DeepEqual(result, test.result) {
+			t.Errorf("%v: Complete() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+func TestIsBoolFlag(t *testing.T) {
+	f := newFlagSet("")
+	f.Bool("b", false, "")
+	f.Int("i", 0, "")
+
+	if !IsBoolFlag(f.Lookup("b")) {
+		t.Error("b should be a bool flag")
+	}
+	if IsBoolFlag(f.Lookup("i")) {
+		t.Error("i should not be a bool flag")
+	}
+}
+
+type CompleterFunc func(*Completion) []string
+
+func (cf CompleterFunc) Complete(c *Completion) []string {
+	return cf(c)
+}
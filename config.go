@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//ConfigValue is a single key = value line of a configuration file.
+type ConfigValue struct {
+	//Key is the name of the flag the value is for.
+	Key string
+
+	//Value is the flag value.
+	Value string
+
+	//Line is the line number of the value in its file starting at 1.
+	Line int
+}
+
+//ConfigSection is a named section of a configuration file.
+type ConfigSection struct {
+	//Name is the section name. It is the empty string for the values before any
+	//section header.
+	Name string
+
+	//Line is the line number of the first header of the section.
+	//It is 0 for the unnamed section.
+	Line int
+
+	//Values are the values of the section in the order they appear.
+	Values []*ConfigValue
+}
+
+//Config is a parsed configuration file.
+//
+//Configuration files consist of key = value lines that are grouped into sections
+//by [section] headers. Keys name flags and values are the values to set them to.
+//Values may be double quoted in Go syntax in order to keep surrounding whitespace.
+//A key without = sets the flag to "true".
+//Lines beginning with # or ; are comments.
+//	# global flags
+//	verbose
+//	timeout = 10s
+//
+//	[remote add]
+//	name = " origin "
+//
+//Whitespace in section names is normalized to a single space so that nested
+//sub-commands may be named by their path.
+type Config struct {
+	//Path is the path of the file the Config was read from.
+	Path string
+
+	//Sections are the sections of the file in the order they first appear.
+	Sections []*ConfigSection
+}
+
+//Section returns the section of c named name or nil if it does not exist.
+func (c *Config) Section(name string) *ConfigSection {
+	for _, section := range c.Sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+//ParseConfig parses a Config from r. Path is used as the Config's Path and in
+//errors.
+//
+//The returned error, if not nil, is a *ConfigError.
+func ParseConfig(path string, r io.Reader) (*Config, error) {
+	c := &Config{Path: path}
+	section := &ConfigSection{}
+	c.Sections = append(c.Sections, section)
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, &ConfigError{Path: path, Line: number, Err: ErrInvalidConfigLine}
+			}
+			name := strings.Join(strings.Fields(line[1:len(line)-1]), " ")
+			if section = c.Section(name); section == nil {
+				section = &ConfigSection{Name: name, Line: number}
+				c.Sections = append(c.Sections, section)
+			}
+			continue
+		}
+
+		value, err := parseConfigValue(line)
+		if err != nil {
+			return nil, &ConfigError{Path: path, Line: number, Err: err}
+		}
+		value.Line = number
+		section.Values = append(section.Values, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+
+	return c, nil
+}
+
+func parseConfigValue(line string) (*ConfigValue, error) {
+	i := strings.Index(line, "=")
+	if i < 0 {
+		i = len(line)
+		line += "=true"
+	}
+
+	key := strings.TrimSpace(line[:i])
+	if len(key) == 0 || strings.ContainsAny(key, " \t") {
+		return nil, ErrInvalidConfigLine
+	}
+
+	value := strings.TrimSpace(line[i+1:])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, ErrInvalidConfigLine
+		}
+		value = unquoted
+	}
+
+	return &ConfigValue{Key: key, Value: value}, nil
+}
+
+//FormatConfigValue returns the key = value line of a configuration file for key
+//and value. Value is quoted if it would not otherwise be parsed as is.
+func FormatConfigValue(key, value string) string {
+	if value != strings.TrimSpace(value) || strings.HasPrefix(value, `"`) {
+		value = strconv.Quote(value)
+	}
+	if len(value) == 0 {
+		return key + " ="
+	}
+	return key + " = " + value
+}
+
+//ReadConfig reads and parses the configuration file at path.
+//It returns nil and a nil error if the file does not exist.
+//
+//The returned error, if not nil, is a *ConfigError.
+func ReadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	defer file.Close()
+
+	return ParseConfig(path, file)
+}
+
+//ConfigFiles are the paths of layered configuration files.
+//Values in Project take precedence over those in User, which take precedence
+//over those in System. Empty paths and files that do not exist are ignored.
+type ConfigFiles struct {
+	//System is the path of the system-wide configuration file,
+	//e.g. /etc/prog/config.
+	System string
+
+	//User is the path of the user's configuration file,
+	//e.g. $HOME/.config/prog/config.
+	User string
+
+	//Project is the path of the project-local configuration file,
+	//e.g. .prog.
+	Project string
+}
+
+//Load reads the files of cf that exist in order of decreasing precedence.
+//The result may be passed to SetFlagsFromConfig.
+//
+//The returned error, if not nil, is a *ConfigError.
+func (cf ConfigFiles) Load() ([]*Config, error) {
+	configs := []*Config{}
+	for _, path := range []string{cf.Project, cf.User, cf.System} {
+		if len(path) == 0 {
+			continue
+		}
+		c, err := ReadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			configs = append(configs, c)
+		}
+	}
+	return configs, nil
+}
+
+//SetFlagsFromConfig sets each flag in f that was not already set to its value
+//in section of configs.
+//Configs are in order of decreasing precedence so that a flag is set from the
+//first Config that has a value for it. Within a Config, later values override
+//earlier ones.
+//
+//Names are the names of the flags that may be set from section. If names is nil,
+//then every flag in f may be set. A key in section that is not in names results
+//in an UnknownConfigKeyError.
+//
+//If p is not nil, then the Source of each flag set is recorded in p.
+//
+//The returned error, if not nil, is a *ConfigError.
+func SetFlagsFromConfig(f *flag.FlagSet, configs []*Config, section string, names []string, p *Provenance) error {
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+	if names == nil {
+		f.VisitAll(func(fl *flag.Flag) {
+			known[fl.Name] = true
+		})
+	}
+
+	set := getSetFlags(f)
+
+	for _, c := range configs {
+		s := c.Section(section)
+		if s == nil {
+			continue
+		}
+
+		setByConfig := map[string]bool{}
+		for _, value := range s.Values {
+			if !known[value.Key] || f.Lookup(value.Key) == nil {
+				return &ConfigError{Path: c.Path, Line: value.Line, Err: UnknownConfigKeyError(value.Key)}
+			}
+			if set[value.Key] {
+				continue
+			}
+			if err := f.Set(value.Key, value.Value); err != nil {
+				return &ConfigError{Path: c.Path, Line: value.Line, Err: err}
+			}
+			setByConfig[value.Key] = true
+			p.record(f.Lookup(value.Key), Source{Kind: SourceConfig, Location: fmt.Sprintf("%s:%d", c.Path, value.Line)})
+		}
+
+		for name := range setByConfig {
+			set[name] = true
+		}
+	}
+
+	return nil
+}
+
+//CheckConfigSections returns an error for the first named section in configs
+//for which known returns false. The unnamed section is always known.
+//
+//The returned error, if not nil, is a *ConfigError wrapping an
+//UnknownConfigSectionError.
+func CheckConfigSections(configs []*Config, known func(name string) bool) error {
+	for _, c := range configs {
+		for _, section := range c.Sections {
+			if len(section.Name) > 0 && !known(section.Name) {
+				return &ConfigError{Path: c.Path, Line: section.Line, Err: UnknownConfigSectionError(section.Name)}
+			}
+		}
+	}
+	return nil
+}
+
+//CheckConfigKeys returns an error for the first key in configs that does not name
+//a flag in the FlagSet returned by flags for the key's section. Sections for
+//which flags returns nil are not checked.
+//
+//The returned error, if not nil, is a *ConfigError wrapping an
+//UnknownConfigKeyError.
+func CheckConfigKeys(configs []*Config, flags func(section string) *flag.FlagSet) error {
+	for _, c := range configs {
+		for _, section := range c.Sections {
+			f := flags(section.Name)
+			if f == nil {
+				continue
+			}
+			for _, value := range section.Values {
+				if f.Lookup(value.Key) == nil {
+					return &ConfigError{Path: c.Path, Line: value.Line, Err: UnknownConfigKeyError(value.Key)}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//SetConfigFileValue sets key in section of the configuration file at path to
+//value.
+//The last value of key in section is replaced if it exists. Otherwise, the value
+//is added to the end of section, which is added to the end of the file if it does
+//not exist. All other lines are preserved. The file and its directory are created
+//if they do not exist.
+//
+//The returned error, if not nil, is a *ConfigError.
+func SetConfigFileValue(path, section, key, value string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return &ConfigError{Path: path, Err: err}
+	}
+	c, err := ParseConfig(path, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	lines := []string{}
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+	line := FormatConfigValue(key, value)
+
+	if s := c.Section(section); s != nil {
+		last, replace := s.Line, 0
+		for _, v := range s.Values {
+			last = v.Line
+			if v.Key == key {
+				replace = v.Line
+			}
+		}
+		if replace > 0 {
+			lines[replace-1] = line
+		} else {
+			lines = append(lines[:last], append([]string{line}, lines[last:]...)...)
+		}
+	} else {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+section+"]", line)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return &ConfigError{Path: path, Err: err}
+	}
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return &ConfigError{Path: path, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	in := strings.Join([]string{
+		"# comment",
+		"verbose",
+		"timeout = 10s",
+		"",
+		"[remote   add]",
+		"; comment",
+		`name = " origin "`,
+		"empty =",
+		"[other]",
+		"[remote add]",
+		"a=b=c",
+	}, "\n")
+
+	c, err := ParseConfig("config", strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Config{
+		Path: "config",
+		Sections: []*ConfigSection{
+			{
+				Values: []*ConfigValue{
+					{Key: "verbose", Value: "true", Line: 2},
+					{Key: "timeout", Value: "10s", Line: 3},
+				},
+			},
+			{
+				Name: "remote add",
+				Line: 5,
+				Values: []*ConfigValue{
+					{Key: "name", Value: " origin ", Line: 7},
+					{Key: "empty", Value: "", Line: 8},
+					{Key: "a", Value: "b=c", Line: 11},
+				},
+			},
+			{
+				Name: "other",
+				Line: 9,
+			},
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("ParseConfig() = %v WANT %v", c, want)
+	}
+
+	if c.Section("none") != nil {
+		t.Fatal("Section(none) should be nil")
+	}
+}
+
+func TestParseConfig_Errors(t *testing.T) {
+	tests := []struct {
+		in   string
+		line int
+	}{
+		{"[section", 1},
+		{"a = b\n= b", 2},
+		{"a b = c", 1},
+		{`a = "b`, 1},
+	}
+
+	for i, test := range tests {
+		_, err := ParseConfig("config", strings.NewReader(test.in))
+
+		want := &ConfigError{Path: "config", Line: test.line, Err: ErrInvalidConfigLine}
+		if !reflect.DeepEqual(err, want) {
+			t.Errorf("%v: err = %v WANT %v", i, err, want)
+		}
+	}
+}
+
+func TestConfigFiles_Load(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	system := filepath.Join(dir, "system")
+	project := filepath.Join(dir, "project")
+	if err := ioutil.WriteFile(system, []byte("a = system"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(project, []byte("a = project"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cf := ConfigFiles{
+		System:  system,
+		User:    filepath.Join(dir, "missing"),
+		Project: project,
+	}
+	configs, err := cf.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configs) != 2 || configs[0].Path != project || configs[1].Path != system {
+		t.Fatalf("configs = %v", configs)
+	}
+
+	if configs, err := (ConfigFiles{}).Load(); len(configs) != 0 || err != nil {
+		t.Fatal(configs, err)
+	}
+}
+
+func TestSetFlagsFromConfig(t *testing.T) {
+	f := NewFlagSet("", nil)
+	a := f.String("a", "default", "")
+	b := f.String("b", "default", "")
+	c := f.String("c", "default", "")
+	d := f.String("d", "default", "")
+	other := f.String("other", "default", "")
+
+	if err := f.Parse(strings.Fields("-a arg")); err != nil {
+		t.Fatal(err)
+	}
+
+	project, _ := ParseConfig("project", strings.NewReader("a = project\nb = project\nb = project2\n[s]\nother = s"))
+	user, _ := ParseConfig("user", strings.NewReader("b = user\nc = user"))
+	system, _ := ParseConfig("system", strings.NewReader("c = system"))
+
+	err := SetFlagsFromConfig(f, []*Config{project, user, system}, "", []string{"a", "b", "c", "d"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := []string{*a, *b, *c, *d, *other}
+	want := []string{"arg", "project2", "user", "default", "default"}
+	if !reflect.DeepEqual(values, want) {
+		t.Fatalf("values = %v WANT %v", values, want)
+	}
+}
+
+func TestSetFlagsFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		in   string
+		line int
+		key  string
+	}{
+		{"\n\nb = 1", 3, "b"},
+		{"unknown = 1", 1, "unknown"},
+		{"a = 1\na = one", 2, ""},
+	}
+
+	for i, test := range tests {
+		f := NewFlagSet("", nil)
+		f.Int("a", 0, "")
+		f.Int("b", 0, "")
+
+		config, _ := ParseConfig("config", strings.NewReader(test.in))
+
+		err := SetFlagsFromConfig(f, []*Config{config}, "", []string{"a"}, nil)
+
+		ce, ok := err.(*ConfigError)
+		if !ok || ce.Path != "config" || ce.Line != test.line {
+			t.Errorf("%v: err = %v", i, err)
+			continue
+		}
+		if len(test.key) > 0 && ce.Err != UnknownConfigKeyError(test.key) {
+			t.Errorf("%v: err = %v WANT key %v", i, err, test.key)
+		}
+	}
+}
+
+func TestSetFlagsFromConfig_NilNamesAllowsAllFlags(t *testing.T) {
+	f := NewFlagSet("", nil)
+	a := f.String("a", "", "")
+
+	config, _ := ParseConfig("config", strings.NewReader("a = config"))
+
+	if err := SetFlagsFromConfig(f, []*Config{config}, "", nil, nil); err != nil || *a != "config" {
+		t.Fatal(*a, err)
+	}
+}
+
+func TestCheckConfigSections(t *testing.T) {
+	first, _ := ParseConfig("first", strings.NewReader("a = 1\n[known]"))
+	second, _ := ParseConfig("second", strings.NewReader("[known]\n\n[unknown]"))
+	known := func(name string) bool {
+		return name == "known"
+	}
+
+	if err := CheckConfigSections([]*Config{first}, known); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CheckConfigSections([]*Config{first, second}, known)
+
+	want := &ConfigError{Path: "second", Line: 3, Err: UnknownConfigSectionError("unknown")}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("err = %v WANT %v", err, want)
+	}
+}
+
+func TestCheckConfigKeys(t *testing.T) {
+	c, _ := ParseConfig("config", strings.NewReader("a = 1\n[other]\nx = 1\n[known]\na = 2\nb = 3"))
+	flags := func(section string) *flag.FlagSet {
+		if section == "other" {
+			return nil
+		}
+		f := newFlagSet("")
+		f.String("a", "", "")
+		return f
+	}
+
+	err := CheckConfigKeys([]*Config{c}, flags)
+
+	want := &ConfigError{Path: "config", Line: 6, Err: UnknownConfigKeyError("b")}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("err = %v WANT %v", err, want)
+	}
+}
+
+func TestFormatConfigValue(t *testing.T) {
+	tests := []struct {
+		key, value, result string
+	}{
+		{"a", "b", "a = b"},
+		{"a", "", "a ="},
+		{"a", " b", `a = " b"`},
+		{"a", `"b"`, `a = "\"b\""`},
+	}
+
+	for i, test := range tests {
+		result := FormatConfigValue(test.key, test.value)
+		if result != test.result {
+			t.Errorf("%v: FormatConfigValue() = %v WANT %v", i, result, test.result)
+		}
+
+		c, err := ParseConfig("", strings.NewReader(result))
+		if err != nil || c.Sections[0].Values[0].Value != test.value {
+			t.Errorf("%v: parsed value = %v, %v", i, c, err)
+		}
+	}
+}
+
+func TestSetConfigFileValue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "prog", "config")
+	sets := [][]string{
+		{"", "a", "1"},
+		{"remote add", "b", "2"},
+		{"", "c", " 3"},
+		{"remote add", "b", "4"},
+		{"other", "d", "5"},
+		{"remote add", "e", "6"},
+	}
+	for _, set := range sets {
+		if err := SetConfigFileValue(path, set[0], set[1], set[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"a = 1",
+		`c = " 3"`,
+		"",
+		"[remote add]",
+		"b = 4",
+		"e = 6",
+		"",
+		"[other]",
+		"d = 5",
+		"",
+	}, "\n")
+	if string(content) != want {
+		t.Fatalf("content =\n%s\nWANT\n%s", content, want)
+	}
+}
+
+func TestSetConfigFileValue_PreservesOtherLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	content := "# comment\na = 1\na = 2\n\n[s]\n; comment\nb = 3\n# trailing"
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetConfigFileValue(path, "", "a", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetConfigFileValue(path, "s", "c", "y"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# comment\na = 1\na = x\n\n[s]\n; comment\nb = 3\nc = y\n# trailing\n"
+	if string(result) != want {
+		t.Fatalf("content =\n%s\nWANT\n%s", result, want)
+	}
+}
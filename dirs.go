@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+//Environment variables of the XDG Base Directory Specification used by NewDirs.
+const (
+	EnvHome          = "HOME"
+	EnvXDGConfigHome = "XDG_CONFIG_HOME"
+	EnvXDGCacheHome  = "XDG_CACHE_HOME"
+	EnvXDGDataHome   = "XDG_DATA_HOME"
+	EnvXDGStateHome  = "XDG_STATE_HOME"
+)
+
+//Dirs are the directories a program stores its files in following the XDG Base
+//Directory Specification. The directories are not guaranteed to exist.
+type Dirs struct {
+	//Config is the directory of user configuration files, e.g. $HOME/.config/prog.
+	Config string
+
+	//Cache is the directory of non-essential cached data, e.g. $HOME/.cache/prog.
+	Cache string
+
+	//Data is the directory of user data files, e.g. $HOME/.local/share/prog.
+	Data string
+
+	//State is the directory of state that should persist between executions but
+	//is not important enough for Data, e.g. $HOME/.local/state/prog.
+	State string
+}
+
+//NewDirs returns the Dirs of the program named name, of which only the base is
+//used.
+//Each directory is the program's name joined to the directory named by its XDG
+//environment variable, e.g. XDG_CONFIG_HOME for Config, or to its default under
+//$HOME if the variable is not set or is not an absolute path.
+//
+//lookupEnv is used to look up environment variables if not nil.
+//Otherwise, os.LookupEnv is used and the home directory of the current user is
+//used if HOME is not set, so that the directories are never relative.
+//With a non-nil lookupEnv that does not set HOME, the defaults are relative to
+//the working directory.
+func NewDirs(name string, lookupEnv func(key string) (string, bool)) *Dirs {
+	home := ""
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+		home = userHomeDir()
+	} else {
+		home, _ = lookupEnv(EnvHome)
+	}
+	name = filepath.Base(name)
+
+	dir := func(key string, elem ...string) string {
+		if value, ok := lookupEnv(key); ok && filepath.IsAbs(value) {
+			return filepath.Join(value, name)
+		}
+		return filepath.Join(append(append([]string{home}, elem...), name)...)
+	}
+
+	return &Dirs{
+		Config: dir(EnvXDGConfigHome, ".config"),
+		Cache:  dir(EnvXDGCacheHome, ".cache"),
+		Data:   dir(EnvXDGDataHome, ".local", "share"),
+		State:  dir(EnvXDGStateHome, ".local", "state"),
+	}
+}
+
+//userHomeDir returns the value of HOME if set and the home directory of the
+//current user otherwise.
+func userHomeDir() string {
+	if home, ok := os.LookupEnv(EnvHome); ok && len(home) > 0 {
+		return home
+	}
+	if u, err := user.Current(); err == nil {
+		return u.HomeDir
+	}
+	return ""
+}
+
+type dirsContextKey struct{}
+
+//NewDirsContext returns a copy of ctx that carries d.
+//SubCommander uses it to pass its Dirs to Execute.
+func NewDirsContext(ctx context.Context, d *Dirs) context.Context {
+	return context.WithValue(ctx, dirsContextKey{}, d)
+}
+
+//DirsFromContext returns the Dirs carried by ctx or nil if there are none.
+func DirsFromContext(ctx context.Context) *Dirs {
+	d, _ := ctx.Value(dirsContextKey{}).(*Dirs)
+	return d
+}
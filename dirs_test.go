@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewDirs(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		dirs *Dirs
+	}{
+		{
+			map[string]string{"HOME": "/home/user"},
+			&Dirs{
+				Config: "/home/user/.config/prog",
+				Cache:  "/home/user/.cache/prog",
+				Data:   "/home/user/.local/share/prog",
+				State:  "/home/user/.local/state/prog",
+			},
+		},
+		{
+			map[string]string{
+				"HOME":            "/home/user",
+				"XDG_CONFIG_HOME": "/config",
+				"XDG_CACHE_HOME":  "/cache",
+				"XDG_DATA_HOME":   "relative",
+				"XDG_STATE_HOME":  "/state",
+			},
+			&Dirs{
+				Config: "/config/prog",
+				Cache:  "/cache/prog",
+				Data:   "/home/user/.local/share/prog",
+				State:  "/state/prog",
+			},
+		},
+	}
+
+	for i, test := range tests {
+		lookupEnv := func(key string) (string, bool) {
+			value, ok := test.env[key]
+			return value, ok
+		}
+		if dirs := NewDirs("/usr/bin/prog", lookupEnv); !reflect.DeepEqual(dirs, test.dirs) {
+			t.Errorf("%v: NewDirs() = %+v WANT %+v", i, dirs, test.dirs)
+		}
+	}
+}
+
+func TestDirsFromContext(t *testing.T) {
+	if DirsFromContext(context.Background()) != nil {
+		t.Fatal("Dirs should be nil")
+	}
+
+	d := &Dirs{}
+	if DirsFromContext(NewDirsContext(context.Background(), d)) != d {
+		t.Fatal("Dirs should be d")
+	}
+}
+
+func TestNewDirs_HomeUnset(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Skip(err)
+	}
+	if home, ok := os.LookupEnv(EnvHome); ok {
+		defer os.Setenv(EnvHome, home)
+	}
+	os.Unsetenv(EnvHome)
+
+	for _, key := range []string{EnvXDGConfigHome, EnvXDGCacheHome, EnvXDGDataHome, EnvXDGStateHome} {
+		if value, ok := os.LookupEnv(key); ok {
+			defer os.Setenv(key, value)
+		}
+		os.Unsetenv(key)
+	}
+
+	want := &Dirs{
+		Config: filepath.Join(u.HomeDir, ".config", "prog"),
+		Cache:  filepath.Join(u.HomeDir, ".cache", "prog"),
+		Data:   filepath.Join(u.HomeDir, ".local", "share", "prog"),
+		State:  filepath.Join(u.HomeDir, ".local", "state", "prog"),
+	}
+	if dirs := NewDirs("prog", nil); !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("NewDirs() = %+v WANT %+v", dirs, want)
+	}
+}
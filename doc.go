@@ -4,6 +4,23 @@
 //Cli is intended to be used by the command and subcommand subpackages that implement
 //their specific use cases.
 //
+//Bind creates a FlagSetter and ParameterSetter from the tagged fields of a struct.
+//
+//ConfigFiles, ParseConfig, and SetFlagsFromConfig read flag values from
+//configuration files in a simple key = value format with [section] headers.
+//
+//Provenance records whether each flag's value came from its default, the command
+//line, the environment, or a configuration file.
+//
+//ParseArgumentsGNU is an alternative to ParseArgumentsInterspersed with the
+//semantics of GNU getopt_long.
+//
+//Secret flags read sensitive values from files or, with ReadSecrets, the standard
+//input and mask them in output.
+//
+//OptionalValueFlags, such as OptionalString, may be given with or without a value,
+//e.g. "-color" or "-color=always".
+//
 //See the command subpackage for writing CLI's that only do "one" thing.
 //And see the subcommand subpackage for writing CLI's with multiple subcommands.
 package cli
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+//EnvOptsName is the last part of the names of environment variables that hold
+//default arguments, e.g. PROG_OPTS. See EnvArgs.
+const EnvOptsName = "opts"
+
+//EnvFlagSetter is a FlagSetter that allows the flags set by another FlagSetter
+//to take their values from environment variables.
+//
+//The usage of each flag set by FlagSetter has its environment variable name
+//appended so that it appears in help output, e.g. "token usage [$PROG_TOKEN]".
+type EnvFlagSetter struct {
+	//FlagSetter sets the flags. It may be nil.
+	FlagSetter
+
+	//Prefix is joined with the name of each flag by EnvName to create the flag's
+	//environment variable name.
+	Prefix string
+
+	//Names, if not nil, receives the environment variable names of the flags
+	//set by FlagSetter keyed by flag name. It may be passed to SetFlagsFromEnv
+	//once arguments are parsed.
+	Names map[string]string
+}
+
+//SetFlags calls e.FlagSetter.SetFlags(f) if e.FlagSetter is not nil and then
+//annotates and records the flags it set.
+func (e *EnvFlagSetter) SetFlags(f *flag.FlagSet) {
+	if e.FlagSetter == nil {
+		return
+	}
+
+	existing := map[string]bool{}
+	f.VisitAll(func(fl *flag.Flag) {
+		existing[fl.Name] = true
+	})
+
+	e.FlagSetter.SetFlags(f)
+
+	f.VisitAll(func(fl *flag.Flag) {
+		if existing[fl.Name] {
+			return
+		}
+		name := EnvName(e.Prefix, fl.Name)
+		fl.Usage = EnvUsage(fl.Usage, name)
+		if e.Names != nil {
+			e.Names[fl.Name] = name
+		}
+	})
+}
+
+//EnvName returns the environment variable name made by joining the non-empty
+//parts with "_" in upper case. All characters that are not letters or digits
+//are replaced by "_".
+//	EnvName("prog", "remote", "dry-run") // "PROG_REMOTE_DRY_RUN"
+func EnvName(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if len(part) > 0 {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Map(func(r rune) rune {
+		if ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(strings.Join(nonEmpty, "_")))
+}
+
+//EnvUsage returns usage with the environment variable name appended for help
+//output.
+func EnvUsage(usage, name string) string {
+	annotation := "[$" + name + "]"
+	if len(usage) == 0 {
+		return annotation
+	}
+	return usage + " " + annotation
+}
+
+//SetFlagsFromEnv sets each flag in f named in names that was not set while parsing
+//arguments to the value of its environment variable, if it is present.
+//Names holds environment variable names by flag name as recorded by EnvFlagSetter.
+//If lookupEnv is nil, then os.LookupEnv is used.
+//If p is not nil, then the Source of each flag set is recorded in p.
+//
+//The returned error, if not nil, is an *EnvValueError.
+func SetFlagsFromEnv(f *flag.FlagSet, names map[string]string, lookupEnv func(string) (string, bool), p *Provenance) error {
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	set := getSetFlags(f)
+
+	var err error
+	f.VisitAll(func(fl *flag.Flag) {
+		name, ok := names[fl.Name]
+		if err != nil || !ok || set[fl.Name] {
+			return
+		}
+		value, ok := lookupEnv(name)
+		if !ok {
+			return
+		}
+		if setErr := f.Set(fl.Name, value); setErr != nil {
+			err = &EnvValueError{Name: name, Value: value, Err: setErr}
+			return
+		}
+		p.record(fl, Source{Kind: SourceEnv, Location: name})
+	})
+	return err
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		parts  []string
+		result string
+	}{
+		{nil, ""},
+		{[]string{"prog", "token"}, "PROG_TOKEN"},
+		{[]string{"prog", "", "remote", "dry-run"}, "PROG_REMOTE_DRY_RUN"},
+		{[]string{"my.prog", "a1"}, "MY_PROG_A1"},
+	}
+
+	for i, test := range tests {
+		if result := EnvName(test.parts...); result != test.result {
+			t.Errorf("%v: EnvName() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+func TestEnvUsage(t *testing.T) {
+	if result := EnvUsage("", "PROG_A"); result != "[$PROG_A]" {
+		t.Error(result)
+	}
+	if result := EnvUsage("usage", "PROG_A"); result != "usage [$PROG_A]" {
+		t.Error(result)
+	}
+}
+
+func TestEnvFlagSetter_SetFlags(t *testing.T) {
+	f := NewFlagSet("", nil)
+	f.String("existing", "", "existing usage")
+
+	names := map[string]string{}
+	e := &EnvFlagSetter{
+		FlagSetter: &stringsFlagSetter{"a", "b"},
+		Prefix:     "prog",
+		Names:      names,
+	}
+	e.SetFlags(f)
+
+	if usage := f.Lookup("existing").Usage; usage != "existing usage" {
+		t.Errorf("existing usage = %v", usage)
+	}
+	if usage := f.Lookup("a").Usage; usage != "a_usage [$PROG_A]" {
+		t.Errorf("a usage = %v", usage)
+	}
+	want := map[string]string{"a": "PROG_A", "b": "PROG_B"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Names = %v WANT %v", names, want)
+	}
+
+	(&EnvFlagSetter{}).SetFlags(f)
+}
+
+func TestSetFlagsFromEnv(t *testing.T) {
+	f := NewFlagSet("", nil)
+	a := f.String("a", "", "")
+	b := f.String("b", "", "")
+	c := f.Int("c", 0, "")
+	d := f.String("d", "default", "")
+
+	if err := f.Parse(strings.Fields("-a arg")); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"PROG_A": "env_a", "PROG_B": "env_b", "PROG_C": "3"}
+	names := map[string]string{"a": "PROG_A", "b": "PROG_B", "c": "PROG_C", "d": "PROG_D"}
+	err := SetFlagsFromEnv(f, names, func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *a != "arg" || *b != "env_b" || *c != 3 || *d != "default" {
+		t.Fatalf("values = %v %v %v %v", *a, *b, *c, *d)
+	}
+}
+
+func TestSetFlagsFromEnv_ReturnsEnvValueError(t *testing.T) {
+	f := NewFlagSet("", nil)
+	f.Int("c", 0, "")
+
+	err := SetFlagsFromEnv(f, map[string]string{"c": "PROG_C"}, func(string) (string, bool) {
+		return "three", true
+	}, nil)
+
+	eve, ok := err.(*EnvValueError)
+	if !ok || eve.Name != "PROG_C" || eve.Value != "three" || eve.Err == nil {
+		t.Fatalf("SetFlagsFromEnv() = %#v", err)
+	}
+}
+
+type stringsFlagSetter []string
+
+func (sfs *stringsFlagSetter) SetFlags(f *flag.FlagSet) {
+	for _, name := range *sfs {
+		f.String(name, "", name+"_usage")
+	}
+}
@@ -59,3 +59,122 @@ func (e *RequiredParameterNotSetError) Error() string {
 //ErrTooManyParameters is an error value that clients can use to signal that
 //too many parameters were provided to a ParameterSetter.
 var ErrTooManyParameters = fmt.Errorf("too many parameters")
+
+//InvalidParameterError is an error that denotes a parameter value could not be
+//converted to the type it is bound to.
+type InvalidParameterError struct {
+	//Name is the name of the Parameter.
+	Name string
+
+	//Value is the parameter value that could not be converted.
+	Value string
+
+	//Err is the conversion error.
+	Err error
+}
+
+//Error provides the error implementation.
+//It returns fmt.Sprintf("invalid value %q for %s %s: %v", e.Value, ParameterName,
+//e.Name, e.Err).
+func (e *InvalidParameterError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s %s: %v", e.Value, ParameterName, e.Name, e.Err)
+}
+
+//BindError is returned from Bind if a struct field cannot be bound.
+type BindError struct {
+	//Field is the name of the struct field.
+	Field string
+
+	//Err describes why Field cannot be bound.
+	Err error
+}
+
+//Error provides the error implementation.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("cannot bind field %s: %v", e.Field, e.Err)
+}
+
+//EnvValueError is an error that denotes the value of an environment variable
+//is not valid for its flag.
+type EnvValueError struct {
+	//Name is the name of the environment variable.
+	Name string
+
+	//Value is the value of the environment variable.
+	Value string
+
+	//Err is the error from setting the flag.
+	Err error
+}
+
+//Error provides the error implementation.
+func (e *EnvValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for environment variable %s: %v", e.Value, e.Name, e.Err)
+}
+
+//ErrUnterminatedQuote is an error that denotes a string split by SplitArgs ends
+//within quotes or after an unquoted backslash.
+var ErrUnterminatedQuote = errors.New("unterminated quote or escape")
+
+//ErrResponseFileCycle is an error that denotes a response file includes itself
+//directly or through other response files.
+var ErrResponseFileCycle = errors.New("response file includes itself")
+
+//ResponseFileError is an error that denotes a response file could not be expanded.
+type ResponseFileError struct {
+	//Path is the path of the response file.
+	Path string
+
+	//Err is the underlying error.
+	Err error
+}
+
+//Error provides the error implementation.
+func (e *ResponseFileError) Error() string {
+	return fmt.Sprintf("response file %s%s: %v", ResponseFilePrefix, e.Path, e.Err)
+}
+
+//ErrInvalidConfigLine is an error that denotes a line of a configuration file
+//is neither a key = value line, a [section] header, nor a comment.
+var ErrInvalidConfigLine = errors.New("invalid line, expected key = value or [section]")
+
+//UnknownConfigKeyError is an error that denotes a key in a configuration file
+//does not name a flag of its section.
+type UnknownConfigKeyError string
+
+//Error provides the error implementation.
+func (e UnknownConfigKeyError) Error() string {
+	return fmt.Sprintf("unknown configuration key %q", string(e))
+}
+
+//UnknownConfigSectionError is an error that denotes a section in a configuration
+//file does not name a sub-command.
+type UnknownConfigSectionError string
+
+//Error provides the error implementation.
+func (e UnknownConfigSectionError) Error() string {
+	return fmt.Sprintf("unknown configuration section %q", string(e))
+}
+
+//ConfigError is an error that denotes a configuration file could not be read
+//or applied. It points at the offending line of the file.
+type ConfigError struct {
+	//Path is the path of the configuration file.
+	Path string
+
+	//Line is the line number of the error starting at 1.
+	//It is 0 if the error does not pertain to a single line.
+	Line int
+
+	//Err is the underlying error.
+	Err error
+}
+
+//Error provides the error implementation.
+//It returns "<path>:<line>: <err>", or "<path>: <err>" if e.Line is 0.
+func (e *ConfigError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+}
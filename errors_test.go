@@ -31,3 +31,55 @@ func TestRequiredParameterNotSetError_Error(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestInvalidParameterError_Error(t *testing.T) {
+	err := &InvalidParameterError{
+		Name:  "port",
+		Value: "http",
+		Err:   fmt.Errorf("error"),
+	}
+
+	if err.Error() != `invalid value "http" for parameter port: error` {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestBindError_Error(t *testing.T) {
+	err := &BindError{
+		Field: "Count",
+		Err:   fmt.Errorf("error"),
+	}
+
+	if err.Error() != "cannot bind field Count: error" {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestConfigError_Error(t *testing.T) {
+	err := &ConfigError{
+		Path: "config",
+		Line: 3,
+		Err:  UnknownConfigKeyError("foo"),
+	}
+	if err.Error() != `config:3: unknown configuration key "foo"` {
+		t.Fatal(err.Error())
+	}
+
+	err = &ConfigError{
+		Path: "config",
+		Err:  UnknownConfigSectionError("foo"),
+	}
+	if err.Error() != `config: unknown configuration section "foo"` {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestResponseFileError_Error(t *testing.T) {
+	err := &ResponseFileError{
+		Path: "args",
+		Err:  ErrResponseFileCycle,
+	}
+	if err.Error() != "response file @args: response file includes itself" {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//ParseArgumentsGNU parses args with the semantics of GNU getopt_long and returns
+//the non-option arguments in params in the same way as ParseArgumentsInterspersed.
+//
+//Flags with single character names are short options and all others are long
+//options. Flags that share the same flag.Value, e.g. defined by
+//	f.BoolVar(&verbose, "v", false, "verbose output")
+//	f.BoolVar(&verbose, "verbose", false, "verbose output")
+//are the short and long names of the same option. Options are given as:
+//	-v -o value -ovalue   short options
+//	-vo value -vovalue    clustered short options, of which only the last may take a value
+//	--verbose --output value --output=value
+//	--verb --out=value    unambiguous prefixes of long options
+//Boolean long options may be given a value with "=", e.g. "--verbose=false".
+//Options whose flag is an OptionalValueFlag take a value only as "--color=WHEN"
+//or "-cWHEN".
+//All arguments after "--" are parameters, as is "-" by itself.
+//
+//Err is flag.ErrHelp if -h or --help is given and not defined. Otherwise, it
+//describes the first invalid option, missing value, or value rejected by its flag.
+func ParseArgumentsGNU(f *flag.FlagSet, args []string) (params []string, err error) {
+	params, _, err = ParseArguments(f, args, ParseOptions{GNU: true})
+	return
+}
+
+//ParseArgumentsGNUPassthrough parses args in the same way as ParseArgumentsGNU
+//except that the arguments after "--" are returned in passthrough instead of params.
+//Passthrough is nil if args does not contain "--" outside of an option's value.
+func ParseArgumentsGNUPassthrough(f *flag.FlagSet, args []string) (params, passthrough []string, err error) {
+	return ParseArguments(f, args, ParseOptions{GNU: true, Passthrough: true})
+}
+
+//ParseFlagsGNU parses the options in args in the same way as ParseArgumentsGNU
+//until the first parameter, or "--", is reached. Like flag.FlagSet.Parse, the
+//remaining arguments are available from f.Args().
+func ParseFlagsGNU(f *flag.FlagSet, args []string) error {
+	remaining, passthrough, err := parseGNU(f, args, false, false)
+	if err != nil {
+		return err
+	}
+	return f.Parse(append(append([]string{DoubleMinus}, remaining...), passthrough...))
+}
+
+//parseGNU parses the options in args. The returned params are the parameters
+//if interspersed is true and the arguments from the first parameter on otherwise.
+//Passthrough are the arguments after "--" if it came before any returned params.
+//If numbers is true, then negative numbers whose first digit is not a short
+//option are parameters.
+func parseGNU(f *flag.FlagSet, args []string, interspersed, numbers bool) (params, passthrough []string, err error) {
+	params = []string{}
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == DoubleMinus:
+			return params, append([]string{}, args[1:]...), nil
+		case len(arg) < 2 || arg[0] != '-' || numbers && isNegativeNumber(arg) && f.Lookup(arg[1:2]) == nil:
+			if !interspersed {
+				return args, nil, nil
+			}
+			params = append(params, arg)
+			args = args[1:]
+		case strings.HasPrefix(arg, "--"):
+			args, err = parseGNULong(f, arg[2:], args[1:])
+		default:
+			args, err = parseGNUShort(f, arg[1:], args[1:])
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return params, nil, nil
+}
+
+//parseGNULong sets the long option of arg, which is "name" or "name=value",
+//and returns the arguments after it.
+func parseGNULong(f *flag.FlagSet, arg string, args []string) ([]string, error) {
+	name, value := arg, ""
+	hasValue := false
+	if i := strings.Index(arg, "="); i >= 0 {
+		name, value, hasValue = arg[:i], arg[i+1:], true
+	}
+
+	fl, err := lookupGNULong(f, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasValue {
+		if IsBoolFlag(fl) {
+			value = "true"
+		} else if ov, ok := fl.Value.(OptionalValueFlag); ok {
+			value = ov.OptionalValue()
+		} else if len(args) == 0 {
+			return nil, fmt.Errorf("option '--%s' requires an argument", fl.Name)
+		} else {
+			value, args = args[0], args[1:]
+		}
+	}
+	return args, setGNU(f, fl, "--"+fl.Name, value)
+}
+
+//lookupGNULong returns the long option named name or by an unambiguous prefix
+//of its name.
+func lookupGNULong(f *flag.FlagSet, name string) (*flag.Flag, error) {
+	if fl := f.Lookup(name); fl != nil && len(name) > 1 {
+		return fl, nil
+	}
+
+	matches := []*flag.Flag{}
+	f.VisitAll(func(fl *flag.Flag) {
+		if len(name) > 0 && len(fl.Name) > 1 && strings.HasPrefix(fl.Name, name) {
+			matches = append(matches, fl)
+		}
+	})
+	if len(matches) == 0 {
+		if name == "help" {
+			return nil, flag.ErrHelp
+		}
+		return nil, fmt.Errorf("unrecognized option '--%s'", name)
+	}
+
+	for _, fl := range matches[1:] {
+		if !isSameValue(fl.Value, matches[0].Value) {
+			possibilities := make([]string, 0, len(matches))
+			for _, match := range matches {
+				possibilities = append(possibilities, "'--"+match.Name+"'")
+			}
+			return nil, fmt.Errorf("option '--%s' is ambiguous; possibilities: %s", name, strings.Join(possibilities, " "))
+		}
+	}
+	return matches[0], nil
+}
+
+//parseGNUShort sets the short options clustered in arg and returns the arguments
+//after them.
+func parseGNUShort(f *flag.FlagSet, arg string, args []string) ([]string, error) {
+	for i, r := range arg {
+		name := string(r)
+		fl := f.Lookup(name)
+		if fl == nil {
+			if name == "h" {
+				return nil, flag.ErrHelp
+			}
+			return nil, fmt.Errorf("invalid option -- '%s'", name)
+		}
+
+		if IsBoolFlag(fl) {
+			if err := setGNU(f, fl, "-"+name, "true"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value := arg[i+len(name):]
+		if ov, ok := fl.Value.(OptionalValueFlag); ok && len(value) == 0 {
+			value = ov.OptionalValue()
+		} else if len(value) == 0 {
+			if len(args) == 0 {
+				return nil, fmt.Errorf("option requires an argument -- '%s'", name)
+			}
+			value, args = args[0], args[1:]
+		}
+		return args, setGNU(f, fl, "-"+name, value)
+	}
+	return args, nil
+}
+
+func setGNU(f *flag.FlagSet, fl *flag.Flag, option, value string) error {
+	if err := f.Set(fl.Name, value); err != nil {
+		return fmt.Errorf("invalid value %q for option %s: %v", value, option, err)
+	}
+	return nil
+}
+
+//isSameValue returns whether or not a and b are the same flag.Value, and so
+//belong to the same option.
+func isSameValue(a, b flag.Value) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta == tb && ta.Comparable() && a == b
+}
+
+//getSetFlags returns the names of the flags that were set in f while parsing,
+//including flags that share a flag.Value with a set flag.
+func getSetFlags(f *flag.FlagSet) map[string]bool {
+	setValues := []flag.Value{}
+	f.Visit(func(fl *flag.Flag) {
+		setValues = append(setValues, fl.Value)
+	})
+
+	set := map[string]bool{}
+	f.VisitAll(func(fl *flag.Flag) {
+		for _, value := range setValues {
+			if isSameValue(fl.Value, value) {
+				set[fl.Name] = true
+			}
+		}
+	})
+	return set
+}
+
+//getGNUOptions returns the flags of f grouped by option in lexicographical order
+//of each option's first flag. Flags of an option share the same flag.Value.
+func getGNUOptions(f *flag.FlagSet) [][]*flag.Flag {
+	options := [][]*flag.Flag{}
+	f.VisitAll(func(fl *flag.Flag) {
+		for i, option := range options {
+			if isSameValue(option[0].Value, fl.Value) {
+				options[i] = append(option, fl)
+				return
+			}
+		}
+		options = append(options, []*flag.Flag{fl})
+	})
+	return options
+}
+
+//GetGNUFlagSetDefaults returns the defaults of f in the same format as
+//GetFlagSetDefaults, except that the names of each option are listed together
+//in the form of ParseArgumentsGNU, e.g. "-v, --verbose" or "-c, --color[=WHEN]".
+func GetGNUFlagSetDefaults(f *flag.FlagSet) string {
+	out := bytes.NewBuffer([]byte{})
+	for _, option := range getGNUOptions(f) {
+		names := make([]string, 0, len(option))
+		usageFlag := option[0]
+		for _, fl := range option {
+			if len(fl.Name) == 1 {
+				names = append(names, "-"+fl.Name)
+			} else {
+				names = append(names, "--"+fl.Name)
+			}
+			if len(usageFlag.Usage) == 0 {
+				usageFlag = fl
+			}
+		}
+		sort.Sort(gnuNames(names))
+
+		fmt.Fprintf(out, "  %s", strings.Join(names, ", "))
+		valueName, usage := flag.UnquoteUsage(usageFlag)
+		switch {
+		case IsOptionalValueFlag(usageFlag) && len(names[len(names)-1]) == 2:
+			fmt.Fprintf(out, "[%s]", valueName)
+		case IsOptionalValueFlag(usageFlag):
+			fmt.Fprintf(out, "[=%s]", valueName)
+		case len(valueName) > 0:
+			fmt.Fprintf(out, " %s", valueName)
+		}
+		if len(names) == 1 && len(names[0]) == 2 && len(valueName) == 0 {
+			fmt.Fprint(out, "\t")
+		} else {
+			fmt.Fprint(out, "\n    \t")
+		}
+		fmt.Fprint(out, strings.Replace(usage, "\n", "\n    \t", -1))
+
+		if !isZeroValue(usageFlag) {
+			if getFlagType(usageFlag) == "string" {
+				fmt.Fprintf(out, " (default %q)", usageFlag.DefValue)
+			} else {
+				fmt.Fprintf(out, " (default %v)", usageFlag.DefValue)
+			}
+		}
+		fmt.Fprint(out, "\n")
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+//gnuNames sorts short option names before long option names.
+type gnuNames []string
+
+func (n gnuNames) Len() int      { return len(n) }
+func (n gnuNames) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n gnuNames) Less(i, j int) bool {
+	if shortI, shortJ := len(n[i]) == 2, len(n[j]) == 2; shortI != shortJ {
+		return shortI
+	}
+	return n[i] < n[j]
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type gnuFlags struct {
+	verbose bool
+	all     bool
+	output  string
+	count   int
+	version bool
+}
+
+func newGNUFlagSet(v *gnuFlags) *flag.FlagSet {
+	f := newFlagSet("")
+	f.BoolVar(&v.verbose, "v", false, "verbose output")
+	f.BoolVar(&v.verbose, "verbose", false, "verbose output")
+	f.BoolVar(&v.all, "a", false, "all")
+	f.StringVar(&v.output, "o", "", "output `file`")
+	f.StringVar(&v.output, "output", "", "output `file`")
+	f.StringVar(&v.output, "out", "", "output `file`")
+	f.IntVar(&v.count, "count", 0, "count")
+	f.BoolVar(&v.version, "version", false, "print the version")
+	return f
+}
+
+func TestParseArgumentsGNU(t *testing.T) {
+	tests := []struct {
+		args   string
+		flags  gnuFlags
+		params []string
+		err    error
+	}{
+		{"", gnuFlags{}, []string{}, nil},
+		{"one -v two - three", gnuFlags{verbose: true}, []string{"one", "two", "-", "three"}, nil},
+		{"-va -o file", gnuFlags{verbose: true, all: true, output: "file"}, []string{}, nil},
+		{"-vofile param", gnuFlags{verbose: true, output: "file"}, []string{"param"}, nil},
+		{"-avo file", gnuFlags{verbose: true, all: true, output: "file"}, []string{}, nil},
+		{"--verbose --output file --count=3", gnuFlags{verbose: true, output: "file", count: 3}, []string{}, nil},
+		{"--output=a=b --verbose=false", gnuFlags{output: "a=b"}, []string{}, nil},
+		{"--verb --cou 2 --vers", gnuFlags{verbose: true, count: 2, version: true}, []string{}, nil},
+		{"--ou file", gnuFlags{output: "file"}, []string{}, nil},
+		{"-v -- -a --count 1", gnuFlags{verbose: true}, []string{"-a", "--count", "1"}, nil},
+		{"--ver", gnuFlags{}, nil, errors.New("option '--ver' is ambiguous; possibilities: '--verbose' '--version'")},
+		{"--other", gnuFlags{}, nil, errors.New("unrecognized option '--other'")},
+		{"--", gnuFlags{}, []string{}, nil},
+		{"-vx", gnuFlags{verbose: true}, nil, errors.New("invalid option -- 'x'")},
+		{"-o", gnuFlags{}, nil, errors.New("option requires an argument -- 'o'")},
+		{"--count", gnuFlags{}, nil, errors.New("option '--count' requires an argument")},
+		{"--count one", gnuFlags{}, nil, errors.New(`invalid value "one" for option --count: parse error`)},
+		{"-h", gnuFlags{}, nil, flag.ErrHelp},
+		{"--help", gnuFlags{}, nil, flag.ErrHelp},
+	}
+
+	for i, test := range tests {
+		v := &gnuFlags{}
+		params, err := ParseArgumentsGNU(newGNUFlagSet(v), strings.Fields(test.args))
+
+		if err != test.err && (err == nil || test.err == nil || !strings.HasPrefix(err.Error(), test.err.Error())) {
+			t.Errorf("%v: err = %v WANT %v", i, err, test.err)
+		}
+		if !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%v: params = %q WANT %q", i, params, test.params)
+		}
+		if err == nil && *v != test.flags {
+			t.Errorf("%v: flags = %+v WANT %+v", i, *v, test.flags)
+		}
+	}
+}
+
+func TestParseArgumentsGNUPassthrough(t *testing.T) {
+	tests := []struct {
+		args        string
+		params      []string
+		passthrough []string
+	}{
+		{"one -v two", []string{"one", "two"}, nil},
+		{"one -v -- -a two", []string{"one"}, []string{"-a", "two"}},
+		{"-o -- --", []string{}, []string{}},
+	}
+
+	for i, test := range tests {
+		params, passthrough, err := ParseArgumentsGNUPassthrough(newGNUFlagSet(&gnuFlags{}), strings.Fields(test.args))
+		if err != nil {
+			t.Fatalf("%v: %v", i, err)
+		}
+		if !reflect.DeepEqual(params, test.params) || !reflect.DeepEqual(passthrough, test.passthrough) {
+			t.Errorf("%v: params, passthrough = %q, %q WANT %q, %q", i, params, passthrough, test.params, test.passthrough)
+		}
+	}
+}
+
+func TestParseFlagsGNU_StopsAtFirstParameter(t *testing.T) {
+	tests := []struct {
+		args string
+		rest []string
+	}{
+		{"-v sub -a", []string{"sub", "-a"}},
+		{"-v -- -a", []string{"-a"}},
+		{"-v", []string{}},
+	}
+
+	for i, test := range tests {
+		v := &gnuFlags{}
+		f := newGNUFlagSet(v)
+		if err := ParseFlagsGNU(f, strings.Fields(test.args)); err != nil {
+			t.Fatalf("%v: %v", i, err)
+		}
+		if !v.verbose || v.all || !reflect.DeepEqual(f.Args(), test.rest) {
+			t.Errorf("%v: flags, args = %+v, %q WANT %q", i, *v, f.Args(), test.rest)
+		}
+	}
+}
+
+func TestSetFlagsFromEnv_DoesNotSetOtherNamesOfSetOption(t *testing.T) {
+	v := &gnuFlags{}
+	f := newGNUFlagSet(v)
+	if _, err := ParseArgumentsGNU(f, []string{"-o", "arg"}); err != nil {
+		t.Fatal(err)
+	}
+
+	names := map[string]string{"output": "PROG_OUTPUT", "count": "PROG_COUNT"}
+	err := SetFlagsFromEnv(f, names, func(string) (string, bool) {
+		return "1", true
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.output != "arg" || v.count != 1 {
+		t.Fatalf("flags = %+v", *v)
+	}
+}
+
+func TestGetGNUFlagSetDefaults(t *testing.T) {
+	f := newGNUFlagSet(&gnuFlags{})
+	f.String("name", "default", "the name")
+	f.Bool("q", false, "quiet")
+
+	want := strings.Join([]string{
+		"  -a\tall",
+		"  --count int",
+		"    \tcount",
+		"  --name string",
+		`    	the name (default "default")`,
+		"  -o, --out, --output file",
+		"    \toutput file",
+		"  -q\tquiet",
+		"  -v, --verbose",
+		"    \tverbose output",
+		"  --version",
+		"    \tprint the version",
+	}, "\n")
+	if defaults := GetGNUFlagSetDefaults(f); defaults != want {
+		t.Fatalf("defaults =\n%v\nWANT\n%v", defaults, want)
+	}
+}
@@ -0,0 +1,6 @@
+//Package man provides a model of roff man pages and the ability to write them.
+//
+//Pages are usually created by the ManPage and ManPages methods of the command
+//and subcommand packages' Commander and SubCommander types.
+//Each Page is then written with Page.Write or WriteFiles.
+package man
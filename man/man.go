@@ -0,0 +1,226 @@
+package man
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//Section names used in generated Pages.
+const (
+	Name        = "NAME"
+	Synopsis    = "SYNOPSIS"
+	Description = "DESCRIPTION"
+	Options     = "OPTIONS"
+	Parameters  = "PARAMETERS"
+	SubCommands = "SUB-COMMANDS"
+	SeeAlso     = "SEE ALSO"
+
+	GlobalOptions = "GLOBAL OPTIONS"
+)
+
+//Page is a single man page.
+type Page struct {
+	//Name is the name of the page, e.g. "prog" or "prog-remote-add".
+	Name string
+
+	//Section is the manual section the page is in, e.g. "1".
+	Section string
+
+	//Date, Source, and Manual are the optional remaining values of the page title.
+	Date   string
+	Source string
+	Manual string
+
+	//Sections are the sections of the page in order.
+	Sections []*Section
+}
+
+//Section is a section in a Page.
+//Items are written before Text.
+type Section struct {
+	//Name is the section's name, e.g. Synopsis.
+	Name string
+
+	//Items are tagged paragraphs in the section.
+	Items []*Item
+
+	//Text is the section's text.
+	//Paragraphs are separated by blank lines.
+	Text string
+}
+
+//Item is a tagged paragraph in a Section, e.g. a flag and its usage.
+type Item struct {
+	//Tag is written in bold.
+	Tag string
+
+	//Arg is written in italics after Tag if it is not empty.
+	Arg string
+
+	//Text describes the Item.
+	Text string
+}
+
+//AddSection adds a Section with name, text and items to p if text or items
+//are not empty.
+func (p *Page) AddSection(name, text string, items ...*Item) {
+	if len(text) == 0 && len(items) == 0 {
+		return
+	}
+	p.Sections = append(p.Sections, &Section{
+		Name:  name,
+		Items: items,
+		Text:  text,
+	})
+}
+
+//FileName returns the conventional file name of p, e.g. "prog-remote-add.1".
+func (p *Page) FileName() string {
+	return p.Name + "." + p.Section
+}
+
+//Write writes p to out in roff format.
+func (p *Page) Write(out io.Writer) error {
+	ew := &errWriter{w: out}
+
+	ew.printf(
+		".TH %s %s %s %s %s\n",
+		quote(strings.ToUpper(p.Name)),
+		quote(p.Section),
+		quote(p.Date),
+		quote(p.Source),
+		quote(p.Manual),
+	)
+
+	for _, section := range p.Sections {
+		ew.printf(".SH %s\n", quote(section.Name))
+		for _, item := range section.Items {
+			ew.printf(".TP\n\\fB%s\\fR", escape(item.Tag))
+			if len(item.Arg) > 0 {
+				ew.printf(" \\fI%s\\fR", escape(item.Arg))
+			}
+			ew.printf("\n")
+			if len(item.Text) > 0 {
+				ew.printf("%s\n", escapeText(item.Text))
+			}
+		}
+		if len(section.Text) > 0 {
+			if len(section.Items) > 0 {
+				ew.printf(".PP\n")
+			}
+			ew.printf("%s\n", escapeText(section.Text))
+		}
+	}
+
+	return ew.err
+}
+
+//WriteFiles writes each of pages to a file named Page.FileName() in dir.
+func WriteFiles(dir string, pages ...*Page) error {
+	for _, p := range pages {
+		if err := writeFile(filepath.Join(dir, p.FileName()), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, p *Page) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+//NewFlagItems returns an Item for each flag in f.
+//The Item's Text includes the flag's default value if it is not the zero value.
+func NewFlagItems(f *flag.FlagSet) []*Item {
+	items := []*Item{}
+	for _, info := range cli.GetFlagInfos(f) {
+		text := info.Usage
+		if len(info.Default) > 0 {
+			text += fmt.Sprintf(" (default %v)", info.Default)
+		}
+		items = append(items, &Item{
+			Tag:  "-" + info.Name,
+			Arg:  info.ValueName,
+			Text: text,
+		})
+	}
+	return items
+}
+
+//NewParameterItems returns an Item for each of params formatted with format.
+func NewParameterItems(params []*cli.Parameter, format func(*cli.Parameter) string) []*Item {
+	items := []*Item{}
+	for _, param := range params {
+		items = append(items, &Item{Tag: format(param)})
+	}
+	return items
+}
+
+//NameText returns the conventional text of the Name Section for a page named
+//name with a short description.
+//Only the first line of description is used.
+func NameText(name, description string) string {
+	description = strings.TrimSpace(strings.SplitN(strings.TrimSpace(description), "\n", 2)[0])
+	if len(description) == 0 {
+		return name
+	}
+	return name + " - " + description
+}
+
+//quote returns value as a double quoted roff macro argument.
+func quote(value string) string {
+	return `"` + strings.Replace(escape(value), `"`, `\(dq`, -1) + `"`
+}
+
+var escaper = strings.NewReplacer(`\`, `\e`, `-`, `\-`)
+
+//escape escapes roff special characters in value.
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+//escapeText escapes value and separates its paragraphs with the .PP macro.
+//Lines that would otherwise be interpreted as control lines are escaped.
+func escapeText(value string) string {
+	lines := strings.Split(strings.TrimSpace(value), "\n")
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		switch {
+		case len(strings.TrimSpace(line)) == 0:
+			line = ".PP"
+		case strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'"):
+			line = `\&` + escape(line)
+		default:
+			line = escape(line)
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+//errWriter retains the first error from writing to w.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
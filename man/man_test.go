@@ -0,0 +1,163 @@
+package man
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gogolfing/cli"
+)
+
+func TestPage_AddSection_IgnoresEmptySections(t *testing.T) {
+	p := &Page{}
+
+	p.AddSection(Description, "")
+	p.AddSection(Options, "", []*Item{}...)
+	p.AddSection(Name, "name")
+
+	want := []*Section{{Name: Name, Text: "name"}}
+	if !reflect.DeepEqual(p.Sections, want) {
+		t.Fatalf("Sections = %v WANT %v", p.Sections, want)
+	}
+}
+
+func TestPage_FileName(t *testing.T) {
+	p := &Page{Name: "prog-sub", Section: "1"}
+
+	if result := p.FileName(); result != "prog-sub.1" {
+		t.Fatal(result)
+	}
+}
+
+func TestPage_Write(t *testing.T) {
+	p := &Page{
+		Name:    "prog",
+		Section: "1",
+		Source:  `say "hi"`,
+	}
+	p.AddSection(Name, NameText("prog", "does things\nand more"))
+	p.AddSection(Description, ".leading dot\n\nback\\slash")
+	p.AddSection(
+		Parameters,
+		"usage",
+		&Item{Tag: "-flag", Arg: "value", Text: "flag usage"},
+		&Item{Tag: "<NAME>"},
+	)
+
+	out := bytes.NewBuffer([]byte{})
+	if err := p.Write(out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `.TH "PROG" "1" "" "say \(dqhi\(dq" ""
+.SH "NAME"
+prog \- does things
+.SH "DESCRIPTION"
+\&.leading dot
+.PP
+back\eslash
+.SH "PARAMETERS"
+.TP
+\fB\-flag\fR \fIvalue\fR
+flag usage
+.TP
+\fB<NAME>\fR
+.PP
+usage
+`
+	if out.String() != want {
+		t.Fatalf("Write() = %v WANT %v", out, want)
+	}
+}
+
+func TestPage_Write_ReturnsWriterError(t *testing.T) {
+	err := errors.New("write error")
+	p := &Page{Name: "prog"}
+	p.AddSection(Name, "prog")
+
+	if result := p.Write(errorWriter{err}); result != err {
+		t.Fatalf("Write() = %v WANT %v", result, err)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "man")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &Page{Name: "prog", Section: "1"}
+	if err := WriteFiles(dir, p); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "prog.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != `.TH "PROG" "1" "" "" ""`+"\n" {
+		t.Fatal(string(content))
+	}
+}
+
+func TestNewFlagItems(t *testing.T) {
+	f := cli.NewFlagSet("", nil)
+	f.Int("count", 2, "number of `times`")
+	f.Bool("v", false, "verbose")
+
+	items := NewFlagItems(f)
+
+	want := []*Item{
+		{Tag: "-count", Arg: "times", Text: "number of times (default 2)"},
+		{Tag: "-v", Text: "verbose"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("NewFlagItems() = %v WANT %v", items, want)
+	}
+}
+
+func TestNewParameterItems(t *testing.T) {
+	params := []*cli.Parameter{
+		{Name: "a"},
+		{Name: "b", Optional: true, Many: true},
+	}
+
+	items := NewParameterItems(params, cli.FormatParameter)
+
+	want := []*Item{{Tag: "<A>"}, {Tag: "[B...]"}}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("NewParameterItems() = %v WANT %v", items, want)
+	}
+}
+
+func TestNameText(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		result      string
+	}{
+		{"prog", "", "prog"},
+		{"prog", "description", "prog - description"},
+		{"prog", "\n first line \nsecond line", "prog - first line"},
+	}
+
+	for i, test := range tests {
+		if result := NameText(test.name, test.description); result != test.result {
+			t.Errorf("%v: NameText() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (ew errorWriter) Write(_ []byte) (int, error) {
+	return 0, ew.err
+}
+
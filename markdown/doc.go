@@ -0,0 +1,8 @@
+//Package markdown provides a simple model of Markdown reference pages and the
+//ability to write them.
+//
+//Pages are usually created by the MarkdownPage and MarkdownPages methods of
+//the command and subcommand packages' Commander and SubCommander types.
+//Output is deterministic so that generated pages may be committed alongside
+//the code they document.
+package markdown
@@ -0,0 +1,170 @@
+package markdown
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//Heading names used in generated Pages.
+const (
+	Synopsis      = "Synopsis"
+	Description   = "Description"
+	Options       = "Options"
+	GlobalOptions = "Global Options"
+	Parameters    = "Parameters"
+	SubCommands   = "Sub-commands"
+	SeeAlso       = "See Also"
+)
+
+//Extension is the file extension of Markdown pages.
+const Extension = ".md"
+
+//FlagTableHeader is the header row of tables created by AddFlagTable.
+var FlagTableHeader = []string{"Name", "Type", "Default", "Usage"}
+
+//Page is a single Markdown page made up of blocks separated by blank lines.
+type Page struct {
+	//Name is the name of the page without Extension, e.g. "prog-remote-add".
+	Name string
+
+	blocks []string
+}
+
+//FileName returns p.Name + Extension.
+func (p *Page) FileName() string {
+	return p.Name + Extension
+}
+
+//AddHeading adds a heading of level (1 for "#") with text to p.
+func (p *Page) AddHeading(level int, text string) {
+	p.add(strings.Repeat("#", level) + " " + text)
+}
+
+//AddParagraph adds text to p if it is not empty.
+func (p *Page) AddParagraph(text string) {
+	if text = strings.TrimSpace(text); len(text) > 0 {
+		p.add(text)
+	}
+}
+
+//AddCode adds text to p as a fenced code block.
+func (p *Page) AddCode(text string) {
+	p.add("```\n" + text + "\n```")
+}
+
+//AddList adds items to p as an unordered list if there are any items.
+func (p *Page) AddList(items ...string) {
+	if len(items) == 0 {
+		return
+	}
+	p.add("* " + strings.Join(items, "\n* "))
+}
+
+//AddTable adds a table with header and rows to p if there are any rows.
+//Each cell is escaped with EscapeCell.
+func (p *Page) AddTable(header []string, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+
+	lines := []string{
+		tableRow(header),
+		tableRow(repeat("---", len(header))),
+	}
+	for _, row := range rows {
+		lines = append(lines, tableRow(row))
+	}
+	p.add(strings.Join(lines, "\n"))
+}
+
+//AddFlagTable adds a table of the flags in f to p with FlagTableHeader if there
+//are any flags.
+func (p *Page) AddFlagTable(f *flag.FlagSet) {
+	rows := [][]string{}
+	for _, info := range cli.GetFlagInfos(f) {
+		defaultValue := ""
+		if len(info.Default) > 0 {
+			defaultValue = Code(info.Default)
+		}
+		rows = append(rows, []string{
+			Code("-" + info.Name),
+			info.Type,
+			defaultValue,
+			info.Usage,
+		})
+	}
+	p.AddTable(FlagTableHeader, rows)
+}
+
+func (p *Page) add(block string) {
+	p.blocks = append(p.blocks, block)
+}
+
+//Write writes p to out.
+func (p *Page) Write(out io.Writer) error {
+	_, err := io.WriteString(out, strings.Join(p.blocks, "\n\n")+"\n")
+	return err
+}
+
+//WriteFiles writes each of pages to a file named Page.FileName() in dir.
+func WriteFiles(dir string, pages ...*Page) error {
+	for _, p := range pages {
+		if err := writeFile(filepath.Join(dir, p.FileName()), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, p *Page) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+//Link returns a relative link with text to the page named pageName.
+func Link(text, pageName string) string {
+	return "[" + text + "](" + pageName + Extension + ")"
+}
+
+//Code returns text as inline code.
+func Code(text string) string {
+	if strings.Contains(text, "`") {
+		return "`` " + text + " ``"
+	}
+	return "`" + text + "`"
+}
+
+var cellEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+//EscapeCell escapes text for use in a table cell.
+func EscapeCell(text string) string {
+	return cellEscaper.Replace(strings.TrimSpace(text))
+}
+
+func tableRow(cells []string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, EscapeCell(cell))
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+func repeat(value string, count int) []string {
+	result := make([]string, count)
+	for i := range result {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogolfing/cli"
+)
+
+func TestPage_FileName(t *testing.T) {
+	p := &Page{Name: "prog-sub"}
+
+	if result := p.FileName(); result != "prog-sub.md" {
+		t.Fatal(result)
+	}
+}
+
+func TestPage_Write(t *testing.T) {
+	p := &Page{Name: "prog"}
+	p.AddHeading(1, "prog")
+	p.AddParagraph("  ")
+	p.AddParagraph(" paragraph ")
+	p.AddCode("prog [options...]")
+	p.AddList()
+	p.AddList(Link("sub", "prog-sub"), "other")
+	p.AddTable([]string{"a", "b"}, nil)
+	p.AddTable([]string{"a", "b"}, [][]string{{"x|y", "multi\nline"}})
+
+	out := bytes.NewBuffer([]byte{})
+	if err := p.Write(out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# prog\n\nparagraph\n\n```\nprog [options...]\n```\n\n" +
+		"* [sub](prog-sub.md)\n* other\n\n" +
+		"| a | b |\n| --- | --- |\n| x\\|y | multi line |\n"
+	if out.String() != want {
+		t.Fatalf("Write() = %q WANT %q", out, want)
+	}
+}
+
+func TestPage_Write_ReturnsWriterError(t *testing.T) {
+	err := errors.New("write error")
+	p := &Page{Name: "prog"}
+
+	if result := p.Write(errorWriter{err}); result != err {
+		t.Fatalf("Write() = %v WANT %v", result, err)
+	}
+}
+
+func TestPage_AddFlagTable(t *testing.T) {
+	f := cli.NewFlagSet("", nil)
+	f.Int("count", 2, "number of `times`")
+	f.Bool("v", false, "verbose")
+
+	p := &Page{}
+	p.AddFlagTable(f)
+	p.AddFlagTable(cli.NewFlagSet("", nil))
+
+	out := bytes.NewBuffer([]byte{})
+	p.Write(out)
+
+	want := "| Name | Type | Default | Usage |\n| --- | --- | --- | --- |\n" +
+		"| `-count` | int | `2` | number of times |\n" +
+		"| `-v` | bool |  | verbose |\n"
+	if out.String() != want {
+		t.Fatalf("AddFlagTable() = %q WANT %q", out, want)
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	p := &Page{Name: "prog"}
+	p.AddHeading(1, "prog")
+	if err := WriteFiles(dir, p); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "prog.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "# prog\n" {
+		t.Fatal(string(content))
+	}
+}
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		text   string
+		result string
+	}{
+		{"-flag", "`-flag`"},
+		{"a`b", "`` a`b ``"},
+	}
+
+	for i, test := range tests {
+		if result := Code(test.text); result != test.result {
+			t.Errorf("%v: Code() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (ew errorWriter) Write(_ []byte) (int, error) {
+	return 0, ew.err
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"strings"
+)
+
+//OptionalValueFlag is implemented by flag.Values whose flags may be given with
+//or without a value, like GNU --color[=WHEN]. For example, both "-color" and
+//"-color=always" set the flag, and "-color always" sets it to its optional value
+//followed by the parameter "always". A value may only be given with "=".
+//
+//Optional value flags are supported by ParseArguments and the parsers built on
+//it, while flag.FlagSet.Parse requires ExpandOptionalValues first.
+//GetFlagSetDefaults renders them as "-color[=WHEN]".
+type OptionalValueFlag interface {
+	flag.Value
+
+	//OptionalValue returns the value the flag is set to when given without one.
+	OptionalValue() string
+}
+
+//IsOptionalValueFlag returns whether or not fl is a flag that may be given
+//without a value. See OptionalValueFlag.
+func IsOptionalValueFlag(fl *flag.Flag) bool {
+	_, ok := fl.Value.(OptionalValueFlag)
+	return ok
+}
+
+//OptionalString is a string OptionalValueFlag.
+type OptionalString struct {
+	p        *string
+	optional string
+}
+
+//NewOptionalString returns an OptionalString that stores its value in p and
+//is set to optional when given without a value.
+func NewOptionalString(p *string, optional string) *OptionalString {
+	return &OptionalString{p: p, optional: optional}
+}
+
+//OptionalStringVar defines an OptionalString flag in f with name and usage that
+//stores its value in p and is set to optional when given without a value.
+//The default is the value of p.
+//	OptionalStringVar(f, &color, "color", "always", "colorize the output `WHEN`")
+func OptionalStringVar(f *flag.FlagSet, p *string, name, optional, usage string) {
+	f.Var(NewOptionalString(p, optional), name, usage)
+}
+
+//Set sets the value of o to value.
+func (o *OptionalString) Set(value string) error {
+	*o.p = value
+	return nil
+}
+
+//String returns the value of o.
+func (o *OptionalString) String() string {
+	if o == nil || o.p == nil {
+		return ""
+	}
+	return *o.p
+}
+
+//OptionalValue returns the value o is set to when given without a value.
+func (o *OptionalString) OptionalValue() string {
+	return o.optional
+}
+
+//ExpandOptionalValues returns args with each optional value flag given without
+//a value in the flags at the start of args, e.g. "-color", replaced by the flag
+//with its optional value, e.g. "-color=always", so that flag.FlagSet.Parse does
+//not take the following argument as its value.
+func ExpandOptionalValues(f *flag.FlagSet, args []string) []string {
+	expanded := append([]string{}, args...)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == DoubleMinus {
+			break
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		fl := f.Lookup(name)
+		if fl == nil {
+			break
+		}
+		if ov, ok := fl.Value.(OptionalValueFlag); ok {
+			expanded[i] = arg + "=" + ov.OptionalValue()
+			continue
+		}
+		if !IsBoolFlag(fl) {
+			i++
+		}
+	}
+	return expanded
+}
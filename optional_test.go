@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArguments_OptionalValueFlags(t *testing.T) {
+	tests := []struct {
+		opts   ParseOptions
+		args   string
+		color  string
+		params []string
+	}{
+		{ParseOptions{}, "", "auto", []string{}},
+		{ParseOptions{}, "-color always", "always", []string{"always"}},
+		{ParseOptions{}, "a -color=never b", "never", []string{"a", "b"}},
+		{ParseOptions{}, "--color= -string -color", "", []string{}},
+		{ParseOptions{}, "-bool -color -- -color", "always", []string{"-color"}},
+		{ParseOptions{StopAtFirstParameter: true}, "-color x -color", "always", []string{"x", "-color"}},
+		{ParseOptions{GNU: true}, "--color never -c -cnever", "never", []string{"never"}},
+		{ParseOptions{GNU: true}, "--col=never --colo", "always", []string{}},
+		{ParseOptions{GNU: true}, "-bc x", "always", []string{"x"}},
+	}
+
+	for i, test := range tests {
+		color := "auto"
+		f := newFlagSet("")
+		f.Bool("bool", false, "")
+		f.Bool("b", false, "")
+		f.String("string", "", "")
+		OptionalStringVar(f, &color, "color", "always", "")
+		OptionalStringVar(f, &color, "c", "always", "")
+
+		params, _, err := ParseArguments(f, strings.Fields(test.args), test.opts)
+		if err != nil {
+			t.Fatalf("%v: %v", i, err)
+		}
+		if color != test.color || !reflect.DeepEqual(params, test.params) {
+			t.Errorf("%v: color, params = %q, %q WANT %q, %q", i, color, params, test.color, test.params)
+		}
+	}
+}
+
+func TestExpandOptionalValues(t *testing.T) {
+	color := ""
+	f := newFlagSet("")
+	f.String("string", "", "")
+	OptionalStringVar(f, &color, "color", "always", "")
+
+	args := strings.Fields("-color -string -color --color p -color")
+	want := strings.Fields("-color=always -string -color --color=always p -color")
+	if expanded := ExpandOptionalValues(f, args); !reflect.DeepEqual(expanded, want) {
+		t.Errorf("expanded = %q WANT %q", expanded, want)
+	}
+	if args[0] != "-color" {
+		t.Errorf("args modified = %q", args)
+	}
+}
+
+func TestGetFlagSetDefaults_OptionalValueFlags(t *testing.T) {
+	color, level := "auto", ""
+	f := newFlagSet("")
+	OptionalStringVar(f, &color, "color", "always", "colorize the output `WHEN`")
+	OptionalStringVar(f, &level, "l", "1", "level")
+
+	want := strings.Join([]string{
+		"  -color[=WHEN]",
+		`    	colorize the output WHEN (default auto)`,
+		"  -l[=value]",
+		"    \tlevel",
+	}, "\n")
+	if defaults := GetFlagSetDefaults(f); defaults != want {
+		t.Errorf("defaults =\n%v\nWANT\n%v", defaults, want)
+	}
+
+	f.Var(f.Lookup("color").Value, "c", "")
+	want = strings.Join([]string{
+		"  -c, --color[=WHEN]",
+		`    	colorize the output WHEN (default auto)`,
+		"  -l[value]",
+		"    \tlevel",
+	}, "\n")
+	if defaults := GetGNUFlagSetDefaults(f); defaults != want {
+		t.Errorf("GNU defaults =\n%v\nWANT\n%v", defaults, want)
+	}
+}
+
+func TestIsOptionalValueFlag(t *testing.T) {
+	f := newFlagSet("")
+	f.String("string", "", "")
+	OptionalStringVar(f, new(string), "color", "always", "")
+
+	if IsOptionalValueFlag(f.Lookup("string")) || !IsOptionalValueFlag(f.Lookup("color")) {
+		t.Fail()
+	}
+}
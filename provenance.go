@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"text/tabwriter"
+)
+
+//Kinds of Sources of flag values.
+const (
+	SourceDefault     = "default"
+	SourceCommandLine = "command line"
+	SourceEnv         = "env"
+	SourceConfig      = "config"
+)
+
+//OptionsFlagUsage is the usage of the flag defined by the OptionsFlag fields of
+//Commander and SubCommander in the command and subcommand subpackages.
+const OptionsFlagUsage = "print the value and source of each option instead of executing"
+
+//Source describes where the value of a flag came from.
+type Source struct {
+	//Kind is one of SourceDefault, SourceCommandLine, SourceEnv, or SourceConfig.
+	Kind string
+
+	//Location is the environment variable name for SourceEnv and "<path>:<line>"
+	//for SourceConfig. It is empty otherwise.
+	Location string
+}
+
+//String returns s.Kind followed by s.Location if it is not empty,
+//e.g. "env PROG_TOKEN".
+func (s Source) String() string {
+	if len(s.Location) == 0 {
+		return s.Kind
+	}
+	return s.Kind + " " + s.Location
+}
+
+//Option is a flag and the Source of its value.
+type Option struct {
+	//Flag is the flag.
+	Flag *flag.Flag
+
+	//Source is where the value of Flag came from.
+	Source Source
+}
+
+//Provenance records the Source of the value of each flag of one or more FlagSets.
+//
+//The flags of a FlagSet are added with AddFlags once arguments are parsed.
+//SetFlagsFromEnv and SetFlagsFromConfig then record the flags they set.
+type Provenance struct {
+	options []*Option
+}
+
+//NewProvenance returns an empty Provenance.
+func NewProvenance() *Provenance {
+	return &Provenance{}
+}
+
+//AddFlags adds the flags of f that are not already in p.
+//The Source of flags that are set in f is SourceCommandLine and SourceDefault
+//for all others. Flags already in p with SourceDefault that are now set in f
+//become SourceCommandLine, so AddFlags may be called before and after parsing
+//arguments in order to keep the Options in the order flags were defined.
+func (p *Provenance) AddFlags(f *flag.FlagSet) {
+	set := getSetFlags(f)
+
+	f.VisitAll(func(fl *flag.Flag) {
+		source := Source{Kind: SourceDefault}
+		if set[fl.Name] {
+			source.Kind = SourceCommandLine
+		}
+		option := p.getOption(fl)
+		if option == nil {
+			p.options = append(p.options, &Option{Flag: fl, Source: source})
+		} else if option.Source.Kind == SourceDefault {
+			option.Source = source
+		}
+	})
+}
+
+//Options returns the Options of p in the order they were added.
+func (p *Provenance) Options() []*Option {
+	return p.options
+}
+
+//Lookup returns the Option of the flag named name or nil if it was not added.
+//If flags of more than one FlagSet have the same name, then the Option added
+//last is returned.
+func (p *Provenance) Lookup(name string) *Option {
+	for i := len(p.options) - 1; i >= 0; i-- {
+		if p.options[i].Flag.Name == name {
+			return p.options[i]
+		}
+	}
+	return nil
+}
+
+//WriteTable writes a table of each Option's name, value, and Source to w.
+func (p *Provenance) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\n", "OPTION", "VALUE", "SOURCE")
+	for _, option := range p.options {
+		fmt.Fprintf(tw, "-%s\t%s\t%s\n", option.Flag.Name, option.Flag.Value, option.Source)
+	}
+	return tw.Flush()
+}
+
+func (p *Provenance) getOption(fl *flag.Flag) *Option {
+	for _, option := range p.options {
+		if option.Flag == fl {
+			return option
+		}
+	}
+	return nil
+}
+
+//record sets the Source of fl if p is not nil.
+func (p *Provenance) record(fl *flag.Flag, source Source) {
+	if p == nil {
+		return
+	}
+	if option := p.getOption(fl); option != nil {
+		option.Source = source
+		return
+	}
+	p.options = append(p.options, &Option{Flag: fl, Source: source})
+}
+
+//RecordEnvArgs records the Source of the flags of f set by parsing envArgs, the
+//default arguments of the environment variable named name, followed by args with
+//the parser selected by opts. Flags set by args are SourceCommandLine, and flags
+//set only by envArgs are SourceEnv with name as the Location.
+//
+//Only the flags named in the arguments are recorded, so RecordEnvArgs may be
+//called before or after f is parsed. It does nothing if envArgs is empty.
+func (p *Provenance) RecordEnvArgs(f *flag.FlagSet, name string, envArgs, args []string, opts ParseOptions) {
+	if len(envArgs) == 0 {
+		return
+	}
+	all := getArgsFlags(f, append(append([]string{}, envArgs...), args...), opts)
+	env := getArgsFlags(f, envArgs, opts)
+	command := getArgsFlags(f, args, opts)
+
+	f.VisitAll(func(fl *flag.Flag) {
+		switch {
+		case !all[fl.Name]:
+		case command[fl.Name]:
+			p.record(fl, Source{Kind: SourceCommandLine})
+		case env[fl.Name]:
+			p.record(fl, Source{Kind: SourceEnv, Location: name})
+		}
+	})
+}
+
+//getArgsFlags returns the names of the flags of f that parsing args with opts
+//sets, including flags that share a flag.Value with a set flag. The flags of f
+//are not set. Arguments that fail to parse end the flags that are returned.
+func getArgsFlags(f *flag.FlagSet, args []string, opts ParseOptions) map[string]bool {
+	scratch := flag.NewFlagSet("", flag.ContinueOnError)
+	scratch.SetOutput(ioutil.Discard)
+
+	options := getGNUOptions(f)
+	for _, option := range options {
+		var value flag.Value = &argsValue{isBool: IsBoolFlag(option[0])}
+		if ov, ok := option[0].Value.(OptionalValueFlag); ok {
+			value = &optionalArgsValue{value.(*argsValue), ov.OptionalValue()}
+		}
+		for _, fl := range option {
+			scratch.Var(value, fl.Name, fl.Usage)
+		}
+	}
+
+	ParseArguments(scratch, args, opts)
+	return getSetFlags(scratch)
+}
+
+//argsValue is a flag.Value that accepts any value in place of the flag.Value of
+//a flag for getArgsFlags.
+type argsValue struct {
+	isBool bool
+}
+
+func (v *argsValue) Set(string) error { return nil }
+func (v *argsValue) String() string   { return "" }
+func (v *argsValue) IsBoolFlag() bool { return v.isBool }
+
+//optionalArgsValue is an argsValue in place of an OptionalValueFlag.
+type optionalArgsValue struct {
+	*argsValue
+	optional string
+}
+
+func (v *optionalArgsValue) OptionalValue() string { return v.optional }
+
+type provenanceContextKey struct{}
+
+//NewProvenanceContext returns a copy of ctx that carries p.
+//Commander and SubCommander use it to pass the Provenance of the executing
+//command's flags to Execute.
+func NewProvenanceContext(ctx context.Context, p *Provenance) context.Context {
+	return context.WithValue(ctx, provenanceContextKey{}, p)
+}
+
+//ProvenanceFromContext returns the Provenance carried by ctx or nil if there is
+//none.
+func ProvenanceFromContext(ctx context.Context) *Provenance {
+	p, _ := ctx.Value(provenanceContextKey{}).(*Provenance)
+	return p
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSource_String(t *testing.T) {
+	if s := (Source{Kind: SourceDefault}).String(); s != "default" {
+		t.Error(s)
+	}
+	if s := (Source{Kind: SourceEnv, Location: "PROG_A"}).String(); s != "env PROG_A" {
+		t.Error(s)
+	}
+}
+
+func TestProvenance_RecordsSourcesOfEachFlag(t *testing.T) {
+	f := NewFlagSet("", nil)
+	f.String("arg", "", "")
+	f.String("env", "", "")
+	f.String("config", "", "")
+	f.String("default", "default", "")
+
+	p := NewProvenance()
+	p.AddFlags(f)
+
+	if err := f.Parse(strings.Fields("-arg arg")); err != nil {
+		t.Fatal(err)
+	}
+	p.AddFlags(f)
+
+	err := SetFlagsFromEnv(f, map[string]string{"env": "PROG_ENV", "arg": "PROG_ARG"}, func(string) (string, bool) {
+		return "env", true
+	}, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := ParseConfig("config", strings.NewReader("\nconfig = config\nenv = config"))
+	if err := SetFlagsFromConfig(f, []*Config{config}, "", nil, p); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"arg":     "command line",
+		"env":     "env PROG_ENV",
+		"config":  "config config:2",
+		"default": "default",
+	}
+	for name, source := range want {
+		if option := p.Lookup(name); option == nil || option.Source.String() != source {
+			t.Errorf("Lookup(%v) = %v WANT %v", name, option, source)
+		}
+	}
+	if p.Lookup("none") != nil {
+		t.Error("Lookup(none) should be nil")
+	}
+	if len(p.Options()) != 4 {
+		t.Errorf("Options() = %v", p.Options())
+	}
+
+	out := &bytes.Buffer{}
+	if err := p.WriteTable(out); err != nil {
+		t.Fatal(err)
+	}
+	wantTable := strings.Join([]string{
+		"OPTION    VALUE    SOURCE",
+		"-arg      arg      command line",
+		"-config   config   config config:2",
+		"-default  default  default",
+		"-env      env      env PROG_ENV",
+		"",
+	}, "\n")
+	if out.String() != wantTable {
+		t.Errorf("WriteTable() =\n%v\nWANT\n%v", out, wantTable)
+	}
+}
+
+func TestProvenance_RecordEnvArgs(t *testing.T) {
+	tests := []struct {
+		envArgs string
+		args    string
+		opts    ParseOptions
+		want    map[string]string
+	}{
+		{
+			"-a a -b b",
+			"-b b param -c c",
+			ParseOptions{},
+			map[string]string{"a": "env PROG_OPTS", "b": "command line", "c": "command line", "v": "default"},
+		},
+		{
+			"-a a",
+			"param -c c",
+			ParseOptions{StopAtFirstParameter: true},
+			map[string]string{"a": "env PROG_OPTS", "c": "default"},
+		},
+		{
+			"-va a --color",
+			"--verbose",
+			ParseOptions{GNU: true},
+			map[string]string{"a": "env PROG_OPTS", "v": "command line", "verbose": "command line", "color": "env PROG_OPTS"},
+		},
+		{
+			"",
+			"-a a",
+			ParseOptions{},
+			map[string]string{"a": "default"},
+		},
+	}
+
+	for i, test := range tests {
+		f := NewFlagSet("", nil)
+		f.String("a", "", "")
+		f.String("b", "", "")
+		f.String("c", "", "")
+		verbose := false
+		f.BoolVar(&verbose, "v", false, "")
+		f.BoolVar(&verbose, "verbose", false, "")
+		color := ""
+		OptionalStringVar(f, &color, "color", "always", "")
+
+		p := NewProvenance()
+		p.AddFlags(f)
+		p.RecordEnvArgs(f, "PROG_OPTS", strings.Fields(test.envArgs), strings.Fields(test.args), test.opts)
+
+		for name, source := range test.want {
+			if option := p.Lookup(name); option == nil || option.Source.String() != source {
+				t.Errorf("%v: Lookup(%v) = %v WANT %v", i, name, option, source)
+			}
+		}
+		if f.NFlag() != 0 {
+			t.Errorf("%v: NFlag() = %v WANT 0", i, f.NFlag())
+		}
+	}
+}
+
+func TestProvenance_LookupReturnsLastAddedFlag(t *testing.T) {
+	first := NewFlagSet("", nil)
+	first.String("a", "first", "")
+	second := NewFlagSet("", nil)
+	second.String("a", "second", "")
+
+	p := NewProvenance()
+	p.AddFlags(first)
+	p.AddFlags(second)
+	p.AddFlags(second)
+
+	if len(p.Options()) != 2 {
+		t.Fatal(p.Options())
+	}
+	if option := p.Lookup("a"); option.Flag != second.Lookup("a") {
+		t.Fatal(option)
+	}
+}
+
+func TestProvenanceFromContext(t *testing.T) {
+	if ProvenanceFromContext(context.Background()) != nil {
+		t.Fatal("Provenance should be nil")
+	}
+
+	p := NewProvenance()
+	if ProvenanceFromContext(NewProvenanceContext(context.Background(), p)) != p {
+		t.Fatal("Provenance should be p")
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+//ResponseFilePrefix is the prefix of arguments that name response files.
+//An argument starting with two prefixes is not a response file and is expanded
+//to itself without the first prefix, e.g. "@@user" to "@user".
+const ResponseFilePrefix = "@"
+
+//ExpandResponseFiles returns args with each argument of the form @path replaced
+//by the arguments read from the response file at path.
+//
+//If the file contains a NUL byte, then its arguments are separated by NUL bytes
+//and used literally, as output by find -print0 or xargs -0. A trailing NUL byte
+//is ignored. Otherwise, the file is split by SplitArgs so that arguments may be
+//separated by spaces or newlines and contain quoted whitespace.
+//
+//Arguments read from response files split by SplitArgs are expanded in turn,
+//while those of NUL separated files, being literal, are not. Relative paths are
+//relative to the working directory.
+//
+//Arguments prefixed by SecretFilePrefix, e.g. "@file:/run/secrets/password",
+//name the file of a Secret and are not expanded.
+//
+//Expansion stops at the first "--", whether in args or a response file, so that
+//the parameters and passthrough arguments after it are never rewritten.
+//
+//The returned error, if not nil, is a *ResponseFileError. Its Err is
+//ErrResponseFileCycle if a response file includes itself.
+func ExpandResponseFiles(args []string) ([]string, error) {
+	return expandResponseFiles(args, nil)
+}
+
+//expandResponseFiles expands args read from the response files in stack.
+func expandResponseFiles(args, stack []string) ([]string, error) {
+	expanded := make([]string, 0, len(args))
+	for i, arg := range args {
+		if containsDoubleMinus(expanded) {
+			return append(expanded, args[i:]...), nil
+		}
+		switch {
+		case arg == DoubleMinus:
+			expanded = append(expanded, arg)
+		case strings.HasPrefix(arg, SecretFilePrefix):
+			expanded = append(expanded, arg)
+		case strings.HasPrefix(arg, ResponseFilePrefix+ResponseFilePrefix):
+			expanded = append(expanded, arg[len(ResponseFilePrefix):])
+		case strings.HasPrefix(arg, ResponseFilePrefix) && len(arg) > len(ResponseFilePrefix):
+			fileArgs, err := readResponseFile(arg[len(ResponseFilePrefix):], stack)
+			if err != nil {
+				return nil, err
+			}
+			expanded = append(expanded, fileArgs...)
+		default:
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, nil
+}
+
+//readResponseFile returns the expanded arguments of the response file at path.
+func readResponseFile(path string, stack []string) ([]string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, &ResponseFileError{Path: path, Err: err}
+	}
+	for _, included := range stack {
+		if included == abs {
+			return nil, &ResponseFileError{Path: path, Err: ErrResponseFileCycle}
+		}
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &ResponseFileError{Path: path, Err: err}
+	}
+
+	if bytes.IndexByte(content, 0) >= 0 {
+		return strings.Split(strings.TrimSuffix(string(content), "\x00"), "\x00"), nil
+	}
+	args, err := SplitArgs(string(content))
+	if err != nil {
+		return nil, &ResponseFileError{Path: path, Err: err}
+	}
+	return expandResponseFiles(args, append(stack, abs))
+}
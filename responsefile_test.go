@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"quoted":  "-name 'a b'\n\"c\\\"d\" e\\ f",
+		"lines":   "one\ntwo\n\nthree\n",
+		"nul":     "a b\x00@@c\x00\x00d\x00@" + filepath.Join(dir, "lines") + "\x00",
+		"nested":  "before @" + filepath.Join(dir, "lines") + " after",
+		"cycle":   "@" + filepath.Join(dir, "include"),
+		"include": "@" + filepath.Join(dir, "cycle"),
+		"invalid": "'unterminated",
+		"minus":   "a -- @b",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string {
+		return "@" + filepath.Join(dir, name)
+	}
+
+	tests := []struct {
+		args     []string
+		expanded []string
+		err      error
+	}{
+		{[]string{}, []string{}, nil},
+		{[]string{"a", "@", "@@b", "-c@d"}, []string{"a", "@", "@b", "-c@d"}, nil},
+		{[]string{"-x", path("quoted"), "y"}, []string{"-x", "-name", "a b", `c"d`, "e f", "y"}, nil},
+		{[]string{path("lines")}, []string{"one", "two", "three"}, nil},
+		{[]string{path("nul")}, []string{"a b", "@@c", "", "d", path("lines")}, nil},
+		{[]string{"a", "--", path("lines"), "@@b"}, []string{"a", "--", path("lines"), "@@b"}, nil},
+		{[]string{path("minus"), path("lines")}, []string{"a", "--", "@b", path("lines")}, nil},
+		{[]string{"-p", "@file:" + filepath.Join(dir, "lines")}, []string{"-p", "@file:" + filepath.Join(dir, "lines")}, nil},
+		{[]string{path("nested"), path("nested")}, []string{"before", "one", "two", "three", "after", "before", "one", "two", "three", "after"}, nil},
+		{
+			[]string{path("cycle")},
+			nil,
+			&ResponseFileError{Path: filepath.Join(dir, "cycle"), Err: ErrResponseFileCycle},
+		},
+		{
+			[]string{path("invalid")},
+			nil,
+			&ResponseFileError{Path: filepath.Join(dir, "invalid"), Err: ErrUnterminatedQuote},
+		},
+	}
+
+	for i, test := range tests {
+		expanded, err := ExpandResponseFiles(test.args)
+		if !reflect.DeepEqual(expanded, test.expanded) || !reflect.DeepEqual(err, test.err) {
+			t.Errorf("%v: ExpandResponseFiles(%q) = %q, %v WANT %q, %v", i, test.args, expanded, err, test.expanded, test.err)
+		}
+	}
+
+	_, err = ExpandResponseFiles([]string{path("missing")})
+	if rfe, ok := err.(*ResponseFileError); !ok || !os.IsNotExist(rfe.Err) {
+		t.Errorf("err = %#v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+//Values of Secret flags that refer to where the secret is read from.
+const (
+	//SecretFilePrefix precedes the path of a file to read a Secret from,
+	//e.g. "@file:/run/secrets/password". ExpandResponseFiles does not expand
+	//arguments with this prefix.
+	SecretFilePrefix = "@file:"
+
+	//SecretStdin is the value of a Secret read from the standard input.
+	SecretStdin = "-"
+)
+
+//SecretMask replaces the non-empty values of Secrets in output.
+const SecretMask = "********"
+
+//ErrSecretStdinReused is returned by ReadSecrets if more than one Secret is to
+//be read from the standard input.
+var ErrSecretStdinReused = errors.New("only one secret may be read from the standard input")
+
+//Secret is a string flag.Value for sensitive values such as passwords.
+//
+//Setting a Secret to a value prefixed by SecretFilePrefix reads the secret from
+//the named file. Setting it to SecretStdin leaves the value SecretStdin until
+//ReadSecrets reads the secret from the standard input of the execution.
+//A trailing newline is removed from the secret read. Other values are used as is.
+//
+//String returns SecretMask for a non-empty secret so that the secret does not
+//appear in help defaults, error messages, or Provenance tables.
+//
+//Fields of type Secret are supported by Bind.
+type Secret string
+
+//SecretVar defines a Secret flag in f with name and usage that stores its value
+//in p. The default is the value of p.
+func SecretVar(f *flag.FlagSet, p *string, name, usage string) {
+	f.Var((*Secret)(p), name, usage)
+}
+
+//Set sets s to value or the secret it refers to.
+func (s *Secret) Set(value string) error {
+	var content []byte
+	var err error
+	switch {
+	case strings.HasPrefix(value, SecretFilePrefix):
+		content, err = ioutil.ReadFile(strings.TrimPrefix(value, SecretFilePrefix))
+	default:
+		*s = Secret(value)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.setContent(content)
+	return nil
+}
+
+//setContent sets s to content without a trailing newline.
+func (s *Secret) setContent(content []byte) {
+	value := string(content)
+	if strings.HasSuffix(value, "\n") {
+		value = strings.TrimSuffix(strings.TrimSuffix(value, "\n"), "\r")
+	}
+	*s = Secret(value)
+}
+
+//ReadSecrets sets the Secret flag of fs whose value is SecretStdin, if any, to
+//the secret read from in, the standard input of the execution. A nil in is empty.
+//fs are all of the FlagSets of an execution, so that the standard input is read
+//at most once. Commander and SubCommander call it once the flags are set from
+//all sources.
+//
+//The returned error is ErrSecretStdinReused if more than one Secret has the value
+//SecretStdin. Flags that share a Secret, such as a short and a long name, are one
+//Secret.
+func ReadSecrets(in io.Reader, fs ...*flag.FlagSet) error {
+	secrets := []*Secret{}
+	found := map[*Secret]bool{}
+	for _, f := range fs {
+		f.VisitAll(func(fl *flag.Flag) {
+			if s, ok := fl.Value.(*Secret); ok && *s == SecretStdin && !found[s] {
+				secrets = append(secrets, s)
+				found[s] = true
+			}
+		})
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	if len(secrets) > 1 {
+		return ErrSecretStdinReused
+	}
+
+	if in == nil {
+		in = strings.NewReader("")
+	}
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	secrets[0].setContent(content)
+	return nil
+}
+
+//String returns SecretMask if s is not empty and the empty string otherwise.
+func (s *Secret) String() string {
+	if s == nil || len(*s) == 0 {
+		return ""
+	}
+	return SecretMask
+}
+
+//IsSecret returns whether or not fl is a Secret flag.
+func IsSecret(fl *flag.Flag) bool {
+	_, ok := fl.Value.(*Secret)
+	return ok
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecret_Set(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{"pw": "file secret\n", "crlf": "crlf\r\n", "lines": "a\nb\n\n"}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		value  string
+		secret Secret
+	}{
+		{"plain", "plain"},
+		{"", ""},
+		{"@file:" + filepath.Join(dir, "pw"), "file secret"},
+		{"@file:" + filepath.Join(dir, "crlf"), "crlf"},
+		{"@file:" + filepath.Join(dir, "lines"), "a\nb\n"},
+		{"file:pw", "file:pw"},
+		{"-", "-"},
+	}
+
+	for i, test := range tests {
+		var s Secret
+		if err := s.Set(test.value); err != nil || s != test.secret {
+			t.Errorf("%v: Set(%q) = %q, %v WANT %q", i, test.value, s, err, test.secret)
+		}
+	}
+
+	var s Secret
+	if err := s.Set("@file:" + filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestReadSecrets(t *testing.T) {
+	tests := []struct {
+		args     string
+		in       io.Reader
+		password string
+		token    string
+		err      error
+	}{
+		{"-p plain", strings.NewReader("stdin secret\n"), "plain", "", nil},
+		{"-p -", strings.NewReader("stdin secret\n"), "stdin secret", "", nil},
+		{"-password -", nil, "", "", nil},
+		{"-p - -token plain", strings.NewReader("stdin secret\r\n"), "stdin secret", "plain", nil},
+		{"-p - -token -", strings.NewReader("stdin secret"), "-", "-", ErrSecretStdinReused},
+	}
+
+	for i, test := range tests {
+		password, token := "", ""
+		f := NewFlagSet("", nil)
+		SecretVar(f, &password, "p", "")
+		f.Var(f.Lookup("p").Value, "password", "")
+		SecretVar(f, &token, "token", "")
+
+		if err := f.Parse(strings.Fields(test.args)); err != nil {
+			t.Fatal(err)
+		}
+		err := ReadSecrets(test.in, f)
+		if password != test.password || token != test.token || err != test.err {
+			t.Errorf("%v: password, token, err = %q, %q, %v WANT %q, %q, %v", i, password, token, err, test.password, test.token, test.err)
+		}
+	}
+}
+
+func TestSecret_IsMaskedInOutput(t *testing.T) {
+	password := "default secret"
+	f := NewFlagSet("", nil)
+	SecretVar(f, &password, "password", "the password")
+	f.String("plain", "", "")
+
+	if !IsSecret(f.Lookup("password")) || IsSecret(f.Lookup("plain")) {
+		t.Fatal("only password should be secret")
+	}
+
+	usage := &bytes.Buffer{}
+	f.SetOutput(usage)
+	f.PrintDefaults()
+	if strings.Contains(usage.String(), "secret") || !strings.Contains(usage.String(), SecretMask) {
+		t.Errorf("usage = %v", usage)
+	}
+
+	if err := f.Parse([]string{"-password", "command line secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if password != "command line secret" {
+		t.Fatalf("password = %v", password)
+	}
+
+	p := NewProvenance()
+	p.AddFlags(f)
+	table := &bytes.Buffer{}
+	if err := p.WriteTable(table); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(table.String(), "secret") || !strings.Contains(table.String(), SecretMask) {
+		t.Errorf("table = %v", table)
+	}
+
+	var empty Secret
+	if empty.String() != "" || (*Secret)(nil).String() != "" {
+		t.Error("empty Secrets should not be masked")
+	}
+}
+
+func TestBind_Secret(t *testing.T) {
+	v := &struct {
+		Password Secret `flag:"password" default:"default"`
+	}{}
+	b, err := Bind(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFlagSet("", b)
+	if fl := f.Lookup("password"); !IsSecret(fl) || fl.DefValue != SecretMask {
+		t.Fatalf("password flag = %+v", fl)
+	}
+	if err := f.Parse([]string{"-password=secret"}); err != nil || v.Password != "secret" {
+		t.Fatalf("Password = %q, %v", v.Password, err)
+	}
+}
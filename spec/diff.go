@@ -0,0 +1,237 @@
+package spec
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+//Change is a single difference between two versions of a Command.
+type Change struct {
+	//Path is the names of the sub-commands from the root to the changed Command
+	//joined by " ". It is empty for the root.
+	Path string
+
+	//Description describes the change, e.g. `removed flag "-count"`.
+	Description string
+}
+
+//String returns c.Description prefixed by c.Path and ": " if c.Path is not empty.
+func (c *Change) String() string {
+	if len(c.Path) == 0 {
+		return c.Description
+	}
+	return c.Path + ": " + c.Description
+}
+
+//Diff holds the differences between two versions of a Command.
+type Diff struct {
+	//Breaking are the changes that may break existing invocations, e.g. removed
+	//sub-commands, aliases, or flags, retyped flags, and parameters that became
+	//required or lost Many.
+	Breaking []*Change
+
+	//Additive are the changes that do not break existing invocations, e.g. added
+	//sub-commands, aliases, flags, and optional parameters, and changed defaults.
+	Additive []*Change
+}
+
+//Compare returns the Diff from old to new.
+//
+//Sub-commands are matched by name. A sub-command in old whose name is an alias
+//in new is considered renamed, which is not a breaking change.
+//Flags are compared at the level they are defined, i.e. moving a flag from
+//a sub-command to a parent is reported as removing and adding the flag.
+//Parameters are compared by position, not name.
+func Compare(old, new *Command) *Diff {
+	d := &Diff{}
+	d.compareCommands(nil, old, new)
+	return d
+}
+
+//Write writes the changes of d to out under "Breaking changes:" and
+//"Additive changes:" headers. Headers without changes are omitted.
+func (d *Diff) Write(out io.Writer) error {
+	ew := &errWriter{w: out}
+	writeChanges(ew, "Breaking changes:", d.Breaking)
+	if len(d.Breaking) > 0 && len(d.Additive) > 0 {
+		ew.printf("\n")
+	}
+	writeChanges(ew, "Additive changes:", d.Additive)
+	return ew.err
+}
+
+func writeChanges(ew *errWriter, header string, changes []*Change) {
+	if len(changes) == 0 {
+		return
+	}
+	ew.printf("%s\n", header)
+	for _, change := range changes {
+		ew.printf("  %v\n", change)
+	}
+}
+
+func (d *Diff) breaking(path []string, format string, args ...interface{}) {
+	d.Breaking = append(d.Breaking, newChange(path, format, args...))
+}
+
+func (d *Diff) additive(path []string, format string, args ...interface{}) {
+	d.Additive = append(d.Additive, newChange(path, format, args...))
+}
+
+func newChange(path []string, format string, args ...interface{}) *Change {
+	return &Change{
+		Path:        strings.Join(path, " "),
+		Description: fmt.Sprintf(format, args...),
+	}
+}
+
+func (d *Diff) compareCommands(path []string, old, new *Command) {
+	if !old.DisallowGlobalFlagsWithSubCommand && new.DisallowGlobalFlagsWithSubCommand {
+		d.breaking(path, "global flags are no longer allowed after the sub-command")
+	} else if old.DisallowGlobalFlagsWithSubCommand && !new.DisallowGlobalFlagsWithSubCommand {
+		d.additive(path, "global flags are now allowed after the sub-command")
+	}
+
+	d.compareFlags(path, "global flag", old.GlobalFlags, new.GlobalFlags)
+	d.compareFlags(path, "flag", old.Flags, new.Flags)
+
+	oldGroup, newGroup := len(old.SubCommands) > 0, len(new.SubCommands) > 0
+	if oldGroup && !newGroup {
+		d.breaking(path, "no longer has sub-commands")
+	} else if !oldGroup && newGroup && len(path) > 0 {
+		d.breaking(path, "now requires a sub-command")
+	}
+	if !oldGroup && !newGroup {
+		d.compareParameters(path, old.Parameters, new.Parameters)
+	}
+
+	d.compareSubCommands(path, old.SubCommands, new.SubCommands)
+}
+
+func (d *Diff) compareSubCommands(path []string, old, new []*Command) {
+	matched := map[*Command]bool{}
+
+	for _, oldSub := range old {
+		newSub := findSubCommand(new, oldSub.Name)
+		if newSub == nil {
+			d.breaking(path, "removed sub-command %q", oldSub.Name)
+			continue
+		}
+		matched[newSub] = true
+		if newSub.Name != oldSub.Name {
+			d.additive(path, "renamed sub-command %q to %q", oldSub.Name, newSub.Name)
+		}
+
+		for _, alias := range oldSub.Aliases {
+			if findSubCommand(new, alias) != newSub {
+				d.breaking(path, "removed alias %q of sub-command %q", alias, oldSub.Name)
+			}
+		}
+		for _, alias := range newSub.Aliases {
+			if alias != oldSub.Name && findSubCommand(old, alias) != oldSub {
+				d.additive(path, "added alias %q of sub-command %q", alias, newSub.Name)
+			}
+		}
+
+		d.compareCommands(append(append([]string{}, path...), newSub.Name), oldSub, newSub)
+	}
+
+	for _, newSub := range new {
+		if !matched[newSub] {
+			d.additive(path, "added sub-command %q", newSub.Name)
+		}
+	}
+}
+
+//findSubCommand returns the Command in subCommands with name as its name or
+//one of its aliases. Names take precedence over aliases.
+func findSubCommand(subCommands []*Command, name string) *Command {
+	for _, c := range subCommands {
+		if c.Name == name {
+			return c
+		}
+	}
+	for _, c := range subCommands {
+		for _, alias := range c.Aliases {
+			if alias == name {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Diff) compareFlags(path []string, kind string, old, new []*Flag) {
+	newFlags := map[string]*Flag{}
+	for _, fl := range new {
+		newFlags[fl.Name] = fl
+	}
+	oldFlags := map[string]*Flag{}
+	for _, fl := range old {
+		oldFlags[fl.Name] = fl
+	}
+
+	for _, oldFlag := range old {
+		newFlag, ok := newFlags[oldFlag.Name]
+		if !ok {
+			d.breaking(path, "removed %s \"-%s\"", kind, oldFlag.Name)
+			continue
+		}
+		if newFlag.Type != oldFlag.Type {
+			d.breaking(path, "changed type of %s \"-%s\" from %s to %s", kind, oldFlag.Name, oldFlag.Type, newFlag.Type)
+		} else if newFlag.Default != oldFlag.Default {
+			d.additive(path, "changed default of %s \"-%s\" from %q to %q", kind, oldFlag.Name, oldFlag.Default, newFlag.Default)
+		}
+	}
+
+	for _, newFlag := range new {
+		if _, ok := oldFlags[newFlag.Name]; !ok {
+			d.additive(path, "added %s \"-%s\"", kind, newFlag.Name)
+		}
+	}
+}
+
+func (d *Diff) compareParameters(path []string, old, new []*Parameter) {
+	for i, oldParam := range old {
+		if i >= len(new) {
+			if len(new) == 0 || !new[len(new)-1].Many {
+				d.breaking(path, "removed parameter %d %q", i+1, oldParam.Name)
+			}
+			continue
+		}
+		newParam := new[i]
+
+		if oldParam.Optional && !newParam.Optional {
+			d.breaking(path, "parameter %d %q is now required", i+1, newParam.Name)
+		} else if !oldParam.Optional && newParam.Optional {
+			d.additive(path, "parameter %d %q is now optional", i+1, newParam.Name)
+		}
+		if oldParam.Many && !newParam.Many {
+			d.breaking(path, "parameter %d %q no longer accepts many values", i+1, newParam.Name)
+		} else if !oldParam.Many && newParam.Many {
+			d.additive(path, "parameter %d %q now accepts many values", i+1, newParam.Name)
+		}
+	}
+
+	for i := len(old); i < len(new); i++ {
+		newParam := new[i]
+		if newParam.Optional {
+			d.additive(path, "added optional parameter %d %q", i+1, newParam.Name)
+		} else {
+			d.breaking(path, "added required parameter %d %q", i+1, newParam.Name)
+		}
+	}
+}
+
+//errWriter retains the first error from writing to w.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
@@ -0,0 +1,185 @@
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	old := &Command{
+		Name:        "prog",
+		GlobalFlags: []*Flag{{Name: "v", Type: TypeBool}, {Name: "config", Type: TypeString}},
+		SubCommands: []*Command{
+			{
+				Name:    "remote",
+				Aliases: []string{"r", "rem"},
+				Flags:   []*Flag{{Name: "timeout", Type: TypeDuration, Default: "1s"}},
+				SubCommands: []*Command{
+					{
+						Name: "add",
+						Parameters: []*Parameter{
+							{Name: "name"},
+							{Name: "url", Optional: true, Many: true},
+						},
+					},
+					{Name: "rm"},
+				},
+			},
+			{Name: "status", Flags: []*Flag{{Name: "count", Type: TypeInt}}},
+			{Name: "list", Parameters: []*Parameter{{Name: "filter"}}},
+		},
+	}
+	new := &Command{
+		Name:                              "prog",
+		DisallowGlobalFlagsWithSubCommand: true,
+		GlobalFlags:                       []*Flag{{Name: "v", Type: TypeBool}, {Name: "q", Type: TypeBool}},
+		SubCommands: []*Command{
+			{
+				Name:    "remote",
+				Aliases: []string{"r", "remotes"},
+				Flags:   []*Flag{{Name: "timeout", Type: TypeDuration, Default: "2s"}},
+				SubCommands: []*Command{
+					{
+						Name: "add",
+						Parameters: []*Parameter{
+							{Name: "name", Optional: true},
+							{Name: "url"},
+							{Name: "extra", Optional: true},
+						},
+					},
+					{Name: "remove", Aliases: []string{"rm"}},
+				},
+			},
+			{Name: "status", Flags: []*Flag{{Name: "count", Type: TypeString}, {Name: "all", Type: TypeBool}}},
+			{Name: "init"},
+		},
+	}
+
+	d := Compare(old, new)
+
+	breaking := []string{
+		"global flags are no longer allowed after the sub-command",
+		`removed global flag "-config"`,
+		`removed alias "rem" of sub-command "remote"`,
+		`remote add: parameter 2 "url" is now required`,
+		`remote add: parameter 2 "url" no longer accepts many values`,
+		`status: changed type of flag "-count" from int to string`,
+		`removed sub-command "list"`,
+	}
+	additive := []string{
+		`added global flag "-q"`,
+		`added alias "remotes" of sub-command "remote"`,
+		`remote: changed default of flag "-timeout" from "1s" to "2s"`,
+		`remote add: parameter 1 "name" is now optional`,
+		`remote add: added optional parameter 3 "extra"`,
+		`remote: renamed sub-command "rm" to "remove"`,
+		`status: added flag "-all"`,
+		`added sub-command "init"`,
+	}
+	if result := changeStrings(d.Breaking); !reflect.DeepEqual(result, breaking) {
+		t.Errorf("Breaking = %q WANT %q", result, breaking)
+	}
+	if result := changeStrings(d.Additive); !reflect.DeepEqual(result, additive) {
+		t.Errorf("Additive = %q WANT %q", result, additive)
+	}
+}
+
+func TestCompare_ParameterAndSubCommandStructureChanges(t *testing.T) {
+	old := &Command{
+		SubCommands: []*Command{
+			{Name: "a", Parameters: []*Parameter{{Name: "p"}, {Name: "q"}}},
+			{Name: "b", Parameters: []*Parameter{{Name: "p"}, {Name: "q"}}},
+			{Name: "c", SubCommands: []*Command{{Name: "d"}}},
+			{Name: "e"},
+		},
+	}
+	new := &Command{
+		SubCommands: []*Command{
+			{Name: "a", Parameters: []*Parameter{{Name: "p"}}},
+			{Name: "b", Parameters: []*Parameter{{Name: "p", Many: true}}},
+			{Name: "c"},
+			{Name: "e", SubCommands: []*Command{{Name: "f"}}, Parameters: []*Parameter{{Name: "new"}}},
+		},
+	}
+
+	d := Compare(old, new)
+
+	breaking := []string{
+		`a: removed parameter 2 "q"`,
+		"c: no longer has sub-commands",
+		`c: removed sub-command "d"`,
+		"e: now requires a sub-command",
+	}
+	additive := []string{
+		`b: parameter 1 "p" now accepts many values`,
+		`e: added sub-command "f"`,
+	}
+	if result := changeStrings(d.Breaking); !reflect.DeepEqual(result, breaking) {
+		t.Errorf("Breaking = %q WANT %q", result, breaking)
+	}
+	if result := changeStrings(d.Additive); !reflect.DeepEqual(result, additive) {
+		t.Errorf("Additive = %q WANT %q", result, additive)
+	}
+}
+
+func TestCompare_IdenticalCommandsHaveNoChanges(t *testing.T) {
+	c := &Command{
+		GlobalFlags: []*Flag{{Name: "v", Type: TypeBool}},
+		SubCommands: []*Command{{Name: "a", Aliases: []string{"b"}, Parameters: []*Parameter{{Name: "p"}}}},
+	}
+
+	d := Compare(c, c)
+
+	if len(d.Breaking) != 0 || len(d.Additive) != 0 {
+		t.Fatalf("Compare() = %v, %v WANT no changes", d.Breaking, d.Additive)
+	}
+}
+
+func TestDiff_Write(t *testing.T) {
+	tests := []struct {
+		d      *Diff
+		result string
+	}{
+		{&Diff{}, ""},
+		{
+			&Diff{Additive: []*Change{{Description: "added"}}},
+			"Additive changes:\n  added\n",
+		},
+		{
+			&Diff{
+				Breaking: []*Change{{Path: "a", Description: "removed"}},
+				Additive: []*Change{{Description: "added"}},
+			},
+			"Breaking changes:\n  a: removed\n\nAdditive changes:\n  added\n",
+		},
+	}
+
+	for i, test := range tests {
+		out := bytes.NewBuffer([]byte{})
+		if err := test.d.Write(out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != test.result {
+			t.Errorf("%v: Write() = %q WANT %q", i, out, test.result)
+		}
+	}
+}
+
+func TestDiff_Write_ReturnsWriterError(t *testing.T) {
+	err := errors.New("write error")
+	d := &Diff{Breaking: []*Change{{Description: "removed"}}}
+
+	if result := d.Write(errorWriter{err}); result != err {
+		t.Fatalf("Write() = %v WANT %v", result, err)
+	}
+}
+
+func changeStrings(changes []*Change) []string {
+	result := []string{}
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	return result
+}
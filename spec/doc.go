@@ -0,0 +1,8 @@
+//Package spec provides a machine-readable description of a command line interface
+//that may be encoded as JSON.
+//
+//Specs are usually created by the Spec methods of the command and subcommand
+//packages' Commander and SubCommander types. They allow tools such as editor
+//helpers, wrappers, and linters to introspect a program without parsing its
+//help output.
+package spec
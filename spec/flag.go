@@ -0,0 +1,128 @@
+package spec
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+//Flag types that may be defined by DefineFlag.
+//These are the types reported by cli.FlagInfo for the flag package's values.
+const (
+	TypeBool     = "bool"
+	TypeDuration = "duration"
+	TypeFloat    = "float"
+	TypeInt      = "int"
+	TypeString   = "string"
+	TypeUint     = "uint"
+)
+
+//FlagTypes are all of the flag types that may be defined by DefineFlag.
+var FlagTypes = []string{TypeBool, TypeDuration, TypeFloat, TypeInt, TypeString, TypeUint}
+
+var errUnknownFlagType = errors.New("unknown flag type")
+
+//DefineFlag defines fl in f with the Go type corresponding to fl.Type: bool,
+//time.Duration, float64, int, string, or uint.
+//An empty fl.Default results in the zero value of the type.
+//An error is returned if fl.Type is not one of FlagTypes or fl.Default cannot
+//be parsed.
+func DefineFlag(f *flag.FlagSet, fl *Flag) error {
+	p, err := NewFlagVar(fl)
+	if err != nil {
+		return err
+	}
+	return DefineFlagVar(f, fl, p)
+}
+
+//NewFlagVar returns a pointer to a new variable of the Go type corresponding to
+//fl.Type, e.g. *int for "int", for use with DefineFlagVar.
+//An error is returned in the same cases as DefineFlag.
+func NewFlagVar(fl *Flag) (interface{}, error) {
+	value, err := fl.newValue()
+	if err != nil {
+		return nil, err
+	}
+	return reflect.New(reflect.TypeOf(value)).Interface(), nil
+}
+
+//DefineFlagVar defines fl in f in the same way as DefineFlag except that the
+//flag's value is stored in p, which must be a pointer to the Go type corresponding
+//to fl.Type as returned by NewFlagVar. The variable p points to is set to the
+//default.
+//An error is returned in the same cases as DefineFlag or if p has the wrong type.
+func DefineFlagVar(f *flag.FlagSet, fl *Flag, p interface{}) error {
+	value, err := fl.newValue()
+	if err != nil {
+		return err
+	}
+	if reflect.TypeOf(p) != reflect.PtrTo(reflect.TypeOf(value)) {
+		return fmt.Errorf("invalid variable of type %T for %s flag", p, fl.Type)
+	}
+
+	switch p := p.(type) {
+	case *bool:
+		f.BoolVar(p, fl.Name, value.(bool), fl.Usage)
+	case *time.Duration:
+		f.DurationVar(p, fl.Name, value.(time.Duration), fl.Usage)
+	case *float64:
+		f.Float64Var(p, fl.Name, value.(float64), fl.Usage)
+	case *int:
+		f.IntVar(p, fl.Name, value.(int), fl.Usage)
+	case *string:
+		f.StringVar(p, fl.Name, value.(string), fl.Usage)
+	case *uint:
+		f.UintVar(p, fl.Name, value.(uint), fl.Usage)
+	}
+	return nil
+}
+
+//newValue returns the parsed default value of fl.
+func (fl *Flag) newValue() (interface{}, error) {
+	var value interface{}
+	var err error
+
+	switch fl.Type {
+	case TypeBool:
+		value, err = parseDefault(fl.Default, false, func(s string) (interface{}, error) {
+			return strconv.ParseBool(s)
+		})
+	case TypeDuration:
+		value, err = parseDefault(fl.Default, time.Duration(0), func(s string) (interface{}, error) {
+			return time.ParseDuration(s)
+		})
+	case TypeFloat:
+		value, err = parseDefault(fl.Default, float64(0), func(s string) (interface{}, error) {
+			return strconv.ParseFloat(s, 64)
+		})
+	case TypeInt:
+		value, err = parseDefault(fl.Default, 0, func(s string) (interface{}, error) {
+			v, err := strconv.ParseInt(s, 0, strconv.IntSize)
+			return int(v), err
+		})
+	case TypeString:
+		value = fl.Default
+	case TypeUint:
+		value, err = parseDefault(fl.Default, uint(0), func(s string) (interface{}, error) {
+			v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+			return uint(v), err
+		})
+	default:
+		return nil, errUnknownFlagType
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s default %q", fl.Type, fl.Default)
+	}
+	return value, nil
+}
+
+func parseDefault(s string, zero interface{}, parse func(string) (interface{}, error)) (interface{}, error) {
+	if len(s) == 0 {
+		return zero, nil
+	}
+	return parse(s)
+}
@@ -0,0 +1,82 @@
+package spec
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefineFlag(t *testing.T) {
+	tests := []struct {
+		fl    *Flag
+		value interface{}
+	}{
+		{&Flag{Type: TypeBool}, false},
+		{&Flag{Type: TypeBool, Default: "true"}, true},
+		{&Flag{Type: TypeDuration, Default: "1m"}, time.Minute},
+		{&Flag{Type: TypeFloat, Default: "1.5"}, 1.5},
+		{&Flag{Type: TypeInt, Default: "-2"}, -2},
+		{&Flag{Type: TypeString, Default: "value"}, "value"},
+		{&Flag{Type: TypeUint}, uint(0)},
+		{&Flag{Type: TypeUint, Default: "0x10"}, uint(16)},
+	}
+
+	for i, test := range tests {
+		test.fl.Name = "name"
+		f := flag.NewFlagSet("", flag.ContinueOnError)
+
+		if err := DefineFlag(f, test.fl); err != nil {
+			t.Errorf("%v: DefineFlag() = %v", i, err)
+			continue
+		}
+
+		value := f.Lookup("name").Value.(flag.Getter).Get()
+		if !reflect.DeepEqual(value, test.value) {
+			t.Errorf("%v: value = %#v WANT %#v", i, value, test.value)
+		}
+	}
+}
+
+func TestDefineFlag_Errors(t *testing.T) {
+	tests := []*Flag{
+		{Name: "name", Type: "unknown"},
+		{Name: "name", Type: TypeBool, Default: "yes please"},
+		{Name: "name", Type: TypeUint, Default: "-1"},
+	}
+
+	for i, test := range tests {
+		f := flag.NewFlagSet("", flag.ContinueOnError)
+
+		if err := DefineFlag(f, test); err == nil {
+			t.Errorf("%v: DefineFlag() error should not be nil", i)
+		}
+		if f.Lookup("name") != nil {
+			t.Errorf("%v: flag should not be defined", i)
+		}
+	}
+}
+
+func TestDefineFlagVar(t *testing.T) {
+	fl := &Flag{Name: "name", Type: TypeInt, Default: "3"}
+	p, err := NewFlagVar(fl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	*p.(*int) = 5
+
+	f := flag.NewFlagSet("", flag.ContinueOnError)
+	if err := DefineFlagVar(f, fl, p); err != nil {
+		t.Fatal(err)
+	}
+	if *p.(*int) != 3 {
+		t.Errorf("value = %v WANT 3", *p.(*int))
+	}
+	if err := f.Parse([]string{"-name", "7"}); err != nil || *p.(*int) != 7 {
+		t.Errorf("value, err = %v, %v WANT 7", *p.(*int), err)
+	}
+
+	if err := DefineFlagVar(flag.NewFlagSet("", flag.ContinueOnError), fl, new(string)); err == nil {
+		t.Error("DefineFlagVar() with *string error should not be nil")
+	}
+}
@@ -0,0 +1,75 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+//Error is an error in a spec and its location.
+type Error struct {
+	//Location is either the "line:column" of malformed JSON or the JSON path of
+	//an invalid value, e.g. "sub_commands[1].flags[0].type".
+	Location string
+
+	//Err is the wrapped error.
+	Err error
+}
+
+//Error is the error implementation for e.
+//It returns e.Location and e.Err.Error() joined by ": ".
+func (e *Error) Error() string {
+	return e.Location + ": " + e.Err.Error()
+}
+
+//Read reads a JSON encoded Command from in with Decode and validates it with Validate.
+func Read(in io.Reader) (*Command, error) {
+	c, err := Decode(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Validate(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+//Decode reads a JSON encoded Command from in without validating it.
+//This allows reading Commands exported from both Commanders and SubCommanders.
+//Malformed JSON results in an *Error with the line and column of the problem.
+func Decode(in io.Reader) (*Command, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Command{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, newDecodeError(data, err)
+	}
+	return c, nil
+}
+
+func newDecodeError(data []byte, err error) error {
+	var offset int64
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		offset = err.Offset
+	case *json.UnmarshalTypeError:
+		offset = err.Offset
+	default:
+		return err
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndex(before, []byte("\n")) - 1
+
+	return &Error{
+		Location: fmt.Sprintf("%d:%d", line, column),
+		Err:      err,
+	}
+}
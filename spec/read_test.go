@@ -0,0 +1,78 @@
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	c, err := Read(strings.NewReader(`{
+  "name": "prog",
+  "sub_commands": [
+    {"name": "sub", "parameters": [{"name": "file"}], "handler": "h"}
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Command{
+		Name: "prog",
+		SubCommands: []*Command{
+			{Name: "sub", Parameters: []*Parameter{{Name: "file"}}, Handler: "h"},
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("Read() = %v WANT %v", c, want)
+	}
+}
+
+func TestRead_ReturnsErrorLocations(t *testing.T) {
+	tests := []struct {
+		in       string
+		location string
+	}{
+		{"{\n  \"name\": \"prog\",\n  \"sub_commands\": [}", "3:20"},
+		{"{\n  \"name\": 1\n}", "2:11"},
+		{`{"sub_commands": [{"name": "a"}, {"name": ""}]}`, "sub_commands[1].name"},
+	}
+
+	for i, test := range tests {
+		_, err := Read(strings.NewReader(test.in))
+
+		specErr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%v: error = %#v WANT *Error", i, err)
+			continue
+		}
+		if specErr.Location != test.location {
+			t.Errorf("%v: Location = %v WANT %v", i, specErr.Location, test.location)
+		}
+	}
+}
+
+func TestRead_ReturnsReaderError(t *testing.T) {
+	err := errors.New("read error")
+
+	if _, result := Read(errorReader{err}); result != err {
+		t.Fatalf("Read() error = %v WANT %v", result, err)
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	err := &Error{Location: "flags[0]", Err: errors.New("error")}
+
+	if result := err.Error(); result != "flags[0]: error" {
+		t.Fatal(result)
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (er errorReader) Read(_ []byte) (int, error) {
+	return 0, er.err
+}
@@ -0,0 +1,109 @@
+package spec
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+
+	"github.com/gogolfing/cli"
+)
+
+//Command describes a command, a sub-command, or a group of sub-commands.
+type Command struct {
+	//Name is the name of the command.
+	Name string `json:"name"`
+
+	//Aliases are the sorted aliases of a sub-command.
+	Aliases []string `json:"aliases,omitempty"`
+
+	//Synopsis is the short description of a sub-command.
+	Synopsis string `json:"synopsis,omitempty"`
+
+	//Description is the full description of the command.
+	Description string `json:"description,omitempty"`
+
+	//GlobalFlags are the flags that apply to all SubCommands of a root command.
+	GlobalFlags []*Flag `json:"global_flags,omitempty"`
+
+	//DisallowGlobalFlagsWithSubCommand denotes whether or not GlobalFlags may
+	//only appear before the first sub-command.
+	DisallowGlobalFlagsWithSubCommand bool `json:"disallow_global_flags_with_sub_command,omitempty"`
+
+	//Flags are the flags defined by the command itself.
+	//Flags of a group of sub-commands are inherited by all of its SubCommands.
+	Flags []*Flag `json:"flags,omitempty"`
+
+	//Parameters are the parameters accepted by the command.
+	Parameters []*Parameter `json:"parameters,omitempty"`
+
+	//ParameterUsage is the usage string describing Parameters.
+	ParameterUsage string `json:"parameter_usage,omitempty"`
+
+	//SubCommands are the sub-commands of a root command or group in lexicographical
+	//order of their names.
+	SubCommands []*Command `json:"sub_commands,omitempty"`
+
+	//Handler is the name of the Go function bound to the command when a spec is
+	//loaded. It is not set by exported specs.
+	Handler string `json:"handler,omitempty"`
+}
+
+//Flag describes a single flag.
+type Flag struct {
+	//Name is the name of the flag without a leading "-".
+	Name string `json:"name"`
+
+	//Type is the name of the flag's type. See cli.FlagInfo.
+	Type string `json:"type"`
+
+	//Default is the flag's default value.
+	//It is omitted if the default is the zero value of the flag's type.
+	Default string `json:"default,omitempty"`
+
+	//Usage is the flag's usage.
+	Usage string `json:"usage,omitempty"`
+}
+
+//Parameter describes a single parameter. See cli.Parameter.
+type Parameter struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+	Many     bool   `json:"many,omitempty"`
+}
+
+//NewFlags returns a Flag for each flag in f in lexicographical order.
+func NewFlags(f *flag.FlagSet) []*Flag {
+	flags := []*Flag{}
+	for _, info := range cli.GetFlagInfos(f) {
+		flags = append(flags, &Flag{
+			Name:    info.Name,
+			Type:    info.Type,
+			Default: info.Default,
+			Usage:   info.Usage,
+		})
+	}
+	return flags
+}
+
+//NewParameters returns a Parameter for each of params.
+func NewParameters(params []*cli.Parameter) []*Parameter {
+	result := make([]*Parameter, 0, len(params))
+	for _, param := range params {
+		result = append(result, &Parameter{
+			Name:     param.Name,
+			Optional: param.Optional,
+			Many:     param.Many,
+		})
+	}
+	return result
+}
+
+//WriteJSON writes c to out as indented JSON followed by a newline.
+func (c *Command) WriteJSON(out io.Writer) error {
+	encoded, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(encoded, '\n'))
+	return err
+}
@@ -0,0 +1,87 @@
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gogolfing/cli"
+)
+
+func TestNewFlags(t *testing.T) {
+	f := cli.NewFlagSet("", nil)
+	f.Int("count", 2, "number of `times`")
+	f.Bool("v", false, "verbose")
+
+	flags := NewFlags(f)
+
+	want := []*Flag{
+		{Name: "count", Type: "int", Default: "2", Usage: "number of times"},
+		{Name: "v", Type: "bool", Usage: "verbose"},
+	}
+	if !reflect.DeepEqual(flags, want) {
+		t.Fatalf("NewFlags() = %v WANT %v", flags, want)
+	}
+}
+
+func TestNewParameters(t *testing.T) {
+	params := NewParameters([]*cli.Parameter{
+		{Name: "a"},
+		{Name: "b", Optional: true, Many: true},
+	})
+
+	want := []*Parameter{{Name: "a"}, {Name: "b", Optional: true, Many: true}}
+	if !reflect.DeepEqual(params, want) {
+		t.Fatalf("NewParameters() = %v WANT %v", params, want)
+	}
+}
+
+func TestCommand_WriteJSON(t *testing.T) {
+	c := &Command{
+		Name:       "prog",
+		Flags:      []*Flag{{Name: "v", Type: "bool"}},
+		Parameters: []*Parameter{{Name: "file", Many: true}},
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	if err := c.WriteJSON(out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "name": "prog",
+  "flags": [
+    {
+      "name": "v",
+      "type": "bool"
+    }
+  ],
+  "parameters": [
+    {
+      "name": "file",
+      "many": true
+    }
+  ]
+}
+`
+	if out.String() != want {
+		t.Fatalf("WriteJSON() = %v WANT %v", out, want)
+	}
+}
+
+func TestCommand_WriteJSON_ReturnsWriterError(t *testing.T) {
+	err := errors.New("write error")
+
+	if result := (&Command{}).WriteJSON(errorWriter{err}); result != err {
+		t.Fatalf("WriteJSON() = %v WANT %v", result, err)
+	}
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (ew errorWriter) Write(_ []byte) (int, error) {
+	return 0, ew.err
+}
@@ -0,0 +1,186 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Errors returned from Validate wrapped in an *Error.
+var (
+	ErrEmptyName                      = errors.New("name must not be empty")
+	ErrRootFlags                      = errors.New("the root command must use global_flags instead of flags")
+	ErrRootParameters                 = errors.New("the root command may not have parameters")
+	ErrGlobalFlags                    = errors.New("only the root command may have global_flags")
+	ErrSubCommandsAndParameters       = errors.New("a command with sub_commands may not have parameters")
+	ErrManyParameterNotLast           = errors.New("only the last parameter may be many")
+	ErrRequiredParameterAfterOptional = errors.New("a required parameter may not follow an optional parameter")
+)
+
+//Walk calls fn for c and each of its descendant SubCommands in depth-first order.
+//path holds c and the Commands from c to the visited Command, which is last.
+//location is the JSON path of the visited Command and is empty for c.
+//Walk stops and returns the first error returned from fn.
+func Walk(c *Command, fn func(location string, path []*Command) error) error {
+	return walk("", []*Command{c}, fn)
+}
+
+func walk(location string, path []*Command, fn func(string, []*Command) error) error {
+	if err := fn(location, path); err != nil {
+		return err
+	}
+	for i, subCommand := range path[len(path)-1].SubCommands {
+		subPath := append(append([]*Command{}, path...), subCommand)
+		if err := walk(JoinLocation(location, "sub_commands", i), subPath, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//JoinLocation returns the JSON path of field within location.
+//If index is not negative, then the path is of the index element of field.
+//	JoinLocation("sub_commands[0]", "flags", 1) // "sub_commands[0].flags[1]"
+func JoinLocation(location, field string, index int) string {
+	if index >= 0 {
+		field = fmt.Sprintf("%s[%d]", field, index)
+	}
+	if len(location) == 0 {
+		return field
+	}
+	return location + "." + field
+}
+
+//Validate determines whether or not c is a valid spec for a root command.
+//The returned error, if any, is an *Error with the location of the first problem.
+//
+//Sub-command names are required and must be unique, along with aliases, within
+//their parent. Flags must have a name, one of FlagTypes, and a default that can
+//be parsed as that type. Flag names must be unique across the global flags and
+//the flags of a command and its ancestors, because those flags are parsed together.
+//Only the last Parameter may be Many and required Parameters may not follow
+//optional ones.
+func Validate(c *Command) error {
+	if len(c.Flags) > 0 {
+		return &Error{"flags", ErrRootFlags}
+	}
+	if len(c.Parameters) > 0 {
+		return &Error{"parameters", ErrRootParameters}
+	}
+
+	return Walk(c, func(location string, path []*Command) error {
+		sc := path[len(path)-1]
+
+		if len(path) > 1 {
+			if err := validateName(JoinLocation(location, "name", -1), sc.Name); err != nil {
+				return err
+			}
+			for i, alias := range sc.Aliases {
+				if err := validateName(JoinLocation(location, "aliases", i), alias); err != nil {
+					return err
+				}
+			}
+			if len(sc.GlobalFlags) > 0 {
+				return &Error{JoinLocation(location, "global_flags", -1), ErrGlobalFlags}
+			}
+		}
+		if len(sc.SubCommands) > 0 && len(sc.Parameters) > 0 {
+			return &Error{JoinLocation(location, "parameters", -1), ErrSubCommandsAndParameters}
+		}
+
+		if err := validateFlags(location, path); err != nil {
+			return err
+		}
+		if err := validateParameters(location, sc.Parameters); err != nil {
+			return err
+		}
+		return validateSubCommandNames(location, sc.SubCommands)
+	})
+}
+
+func validateName(location, name string) error {
+	if len(name) == 0 {
+		return &Error{location, ErrEmptyName}
+	}
+	if strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\r\n") {
+		return &Error{location, fmt.Errorf("invalid name %q", name)}
+	}
+	return nil
+}
+
+func validateSubCommandNames(location string, subCommands []*Command) error {
+	seen := map[string]bool{}
+	for i, subCommand := range subCommands {
+		subLocation := JoinLocation(location, "sub_commands", i)
+		names := append([]string{subCommand.Name}, subCommand.Aliases...)
+		for j, name := range names {
+			if seen[name] {
+				nameLocation := JoinLocation(subLocation, "name", -1)
+				if j > 0 {
+					nameLocation = JoinLocation(subLocation, "aliases", j-1)
+				}
+				return &Error{nameLocation, fmt.Errorf("duplicate sub-command name %q", name)}
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
+
+//validateFlags validates the flags of the last Command in path and that their
+//names are unique among the global flags and flags of all Commands in path.
+func validateFlags(location string, path []*Command) error {
+	c := path[len(path)-1]
+	field, flags := "global_flags", c.GlobalFlags
+	seen := map[string]bool{}
+	if len(path) > 1 {
+		field, flags = "flags", c.Flags
+		for _, fl := range path[0].GlobalFlags {
+			seen[fl.Name] = true
+		}
+		for _, ancestor := range path[1 : len(path)-1] {
+			for _, fl := range ancestor.Flags {
+				seen[fl.Name] = true
+			}
+		}
+	}
+
+	for i, fl := range flags {
+		flagLocation := JoinLocation(location, field, i)
+		if err := validateName(JoinLocation(flagLocation, "name", -1), fl.Name); err != nil {
+			return err
+		}
+		if seen[fl.Name] {
+			return &Error{JoinLocation(flagLocation, "name", -1), fmt.Errorf("duplicate flag name %q", fl.Name)}
+		}
+		seen[fl.Name] = true
+
+		if _, err := fl.newValue(); err != nil {
+			field := "default"
+			if err == errUnknownFlagType {
+				field = "type"
+				err = fmt.Errorf("unknown flag type %q, must be one of %s", fl.Type, strings.Join(FlagTypes, ", "))
+			}
+			return &Error{JoinLocation(flagLocation, field, -1), err}
+		}
+	}
+	return nil
+}
+
+func validateParameters(location string, params []*Parameter) error {
+	optional := false
+	for i, param := range params {
+		paramLocation := JoinLocation(location, "parameters", i)
+		if len(param.Name) == 0 {
+			return &Error{JoinLocation(paramLocation, "name", -1), ErrEmptyName}
+		}
+		if param.Many && i != len(params)-1 {
+			return &Error{JoinLocation(paramLocation, "many", -1), ErrManyParameterNotLast}
+		}
+		if optional && !param.Optional {
+			return &Error{JoinLocation(paramLocation, "optional", -1), ErrRequiredParameterAfterOptional}
+		}
+		optional = optional || param.Optional
+	}
+	return nil
+}
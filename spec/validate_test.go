@@ -0,0 +1,159 @@
+package spec
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJoinLocation(t *testing.T) {
+	tests := []struct {
+		location string
+		field    string
+		index    int
+		result   string
+	}{
+		{"", "name", -1, "name"},
+		{"", "sub_commands", 0, "sub_commands[0]"},
+		{"sub_commands[0]", "flags", 1, "sub_commands[0].flags[1]"},
+	}
+
+	for i, test := range tests {
+		if result := JoinLocation(test.location, test.field, test.index); result != test.result {
+			t.Errorf("%v: JoinLocation() = %v WANT %v", i, result, test.result)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	c := &Command{
+		SubCommands: []*Command{
+			{Name: "a", SubCommands: []*Command{{Name: "b"}}},
+			{Name: "c"},
+		},
+	}
+
+	visited := []string{}
+	Walk(c, func(location string, path []*Command) error {
+		names := []string{}
+		for _, c := range path {
+			names = append(names, c.Name)
+		}
+		visited = append(visited, location+"="+strings.Join(names, "/"))
+		return nil
+	})
+
+	want := []string{
+		"=",
+		"sub_commands[0]=/a",
+		"sub_commands[0].sub_commands[0]=/a/b",
+		"sub_commands[1]=/c",
+	}
+	if !reflect.DeepEqual(visited, want) {
+		t.Fatalf("visited = %v WANT %v", visited, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		c        *Command
+		location string
+		err      string
+	}{
+		{
+			&Command{Name: "prog"},
+			"",
+			"",
+		},
+		{
+			&Command{Flags: []*Flag{{Name: "a", Type: TypeBool}}},
+			"flags",
+			ErrRootFlags.Error(),
+		},
+		{
+			&Command{Parameters: []*Parameter{{Name: "a"}}},
+			"parameters",
+			ErrRootParameters.Error(),
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "-a"}}},
+			"sub_commands[0].name",
+			`invalid name "-a"`,
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "a", Aliases: []string{""}}}},
+			"sub_commands[0].aliases[0]",
+			ErrEmptyName.Error(),
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "a"}, {Name: "b", Aliases: []string{"a"}}}},
+			"sub_commands[1].aliases[0]",
+			`duplicate sub-command name "a"`,
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "a", GlobalFlags: []*Flag{{Name: "g", Type: TypeBool}}}}},
+			"sub_commands[0].global_flags",
+			ErrGlobalFlags.Error(),
+		},
+		{
+			&Command{SubCommands: []*Command{{
+				Name:        "a",
+				Parameters:  []*Parameter{{Name: "p"}},
+				SubCommands: []*Command{{Name: "b"}},
+			}}},
+			"sub_commands[0].parameters",
+			ErrSubCommandsAndParameters.Error(),
+		},
+		{
+			&Command{GlobalFlags: []*Flag{{Name: "g", Type: "bytes"}}},
+			"global_flags[0].type",
+			`unknown flag type "bytes", must be one of bool, duration, float, int, string, uint`,
+		},
+		{
+			&Command{GlobalFlags: []*Flag{{Name: "g", Type: TypeInt, Default: "one"}}},
+			"global_flags[0].default",
+			`invalid int default "one"`,
+		},
+		{
+			&Command{
+				GlobalFlags: []*Flag{{Name: "g", Type: TypeInt}},
+				SubCommands: []*Command{{
+					Name:        "a",
+					Flags:       []*Flag{{Name: "f", Type: TypeInt}},
+					SubCommands: []*Command{{Name: "b", Flags: []*Flag{{Name: "f", Type: TypeInt}}}},
+				}},
+			},
+			"sub_commands[0].sub_commands[0].flags[0].name",
+			`duplicate flag name "f"`,
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "a", Parameters: []*Parameter{{Name: "p", Many: true}, {Name: "q"}}}}},
+			"sub_commands[0].parameters[0].many",
+			ErrManyParameterNotLast.Error(),
+		},
+		{
+			&Command{SubCommands: []*Command{{Name: "a", Parameters: []*Parameter{{Name: "p", Optional: true}, {Name: "q"}}}}},
+			"sub_commands[0].parameters[1].optional",
+			ErrRequiredParameterAfterOptional.Error(),
+		},
+	}
+
+	for i, test := range tests {
+		err := Validate(test.c)
+
+		if len(test.err) == 0 {
+			if err != nil {
+				t.Errorf("%v: Validate() = %v WANT nil", i, err)
+			}
+			continue
+		}
+		specErr, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%v: Validate() = %#v WANT *Error", i, err)
+			continue
+		}
+		if specErr.Location != test.location || specErr.Err.Error() != test.err {
+			t.Errorf("%v: Validate() = %v WANT %v: %v", i, specErr, test.location, test.err)
+		}
+	}
+}
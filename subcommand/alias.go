@@ -0,0 +1,130 @@
+package subcommand
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gogolfing/cli"
+)
+
+//AliasesName is the heading of the user aliases listed by RegisterList.
+const AliasesName = "aliases"
+
+//userAlias is an alias defined in the configuration section of
+//SubCommander.AliasSection.
+type userAlias struct {
+	//expansion is the unsplit value of the alias.
+	expansion string
+
+	//path and line locate the alias in its configuration file.
+	path string
+	line int
+}
+
+//getUserAliases returns the user aliases defined in configs by name.
+//Aliases in configs of higher precedence, and later in the same file, win.
+func (sc *SubCommander) getUserAliases(configs []*cli.Config) map[string]*userAlias {
+	aliases := map[string]*userAlias{}
+	if len(sc.AliasSection) == 0 {
+		return aliases
+	}
+	for i := len(configs) - 1; i >= 0; i-- {
+		section := configs[i].Section(sc.AliasSection)
+		if section == nil {
+			continue
+		}
+		for _, value := range section.Values {
+			aliases[value.Key] = &userAlias{
+				expansion: value.Value,
+				path:      configs[i].Path,
+				line:      value.Line,
+			}
+		}
+	}
+	return aliases
+}
+
+//checkUserAliases returns an error if any user alias in configs shadows the name
+//or alias of a registered SubCommand.
+//
+//The returned error, if not nil, is a *cli.ConfigError wrapping an
+//AliasShadowsSubCommandError.
+func (sc *SubCommander) checkUserAliases(configs []*cli.Config) error {
+	if len(sc.AliasSection) == 0 {
+		return nil
+	}
+	for _, c := range configs {
+		section := c.Section(sc.AliasSection)
+		if section == nil {
+			continue
+		}
+		for _, value := range section.Values {
+			if sc.getSubCommand(value.Key) != nil {
+				return &cli.ConfigError{Path: c.Path, Line: value.Line, Err: AliasShadowsSubCommandError(value.Key)}
+			}
+		}
+	}
+	return nil
+}
+
+//expandUserAliases replaces the first remaining argument of f with the arguments
+//of its user alias in configs and parses them with f until the first remaining
+//argument is not a user alias.
+//
+//The returned error is a RecursiveAliasError if an alias expands to itself, a
+//*cli.ConfigError if an alias cannot be split, or an error from parsing f.
+func (sc *SubCommander) expandUserAliases(f *flag.FlagSet, configs []*cli.Config) error {
+	aliases := sc.getUserAliases(configs)
+	expanded := map[string]bool{}
+	for f.NArg() > 0 && sc.getSubCommand(f.Arg(0)) == nil {
+		name := f.Arg(0)
+		alias, ok := aliases[name]
+		if !ok {
+			return nil
+		}
+		if expanded[name] {
+			return RecursiveAliasError(name)
+		}
+		expanded[name] = true
+
+		args, err := cli.SplitArgs(alias.expansion)
+		if err == nil && len(args) == 0 {
+			err = ErrEmptyAlias
+		}
+		if err != nil {
+			return &cli.ConfigError{Path: alias.path, Line: alias.line, Err: err}
+		}
+		if err := sc.parseFlags(f, append(args, f.Args()[1:]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//getUserAliasesUsage returns the sorted user aliases in configs with their
+//expansions or the empty string if there are none.
+func (sc *SubCommander) getUserAliasesUsage(configs []*cli.Config) string {
+	aliases := sc.getUserAliases(configs)
+	if len(aliases) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(out, "%s:", AliasesName)
+
+	pad := int(math.Max(16, float64(maxLen(names)+4)))
+	for _, name := range names {
+		fmt.Fprintf(out, "\n  %s%s%s", name, padRight(pad, name), aliases[name].expansion)
+	}
+
+	return out.String()
+}
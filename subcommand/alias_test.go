@@ -0,0 +1,146 @@
+package subcommand
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func newAliasSubCommander(dir string) (*SubCommander, *clitest.SimpleFlagSetter, *clitest.SimpleFlagSetter, *[]string) {
+	gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+	afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+	params := &[]string{}
+
+	ps := &clitest.ParameterSetterStruct{
+		SetParametersValue: func(p []string) error {
+			*params = p
+			return nil
+		},
+	}
+
+	sc := &SubCommander{
+		GlobalFlags:  gfs,
+		ConfigFiles:  getConfigFiles(dir),
+		AliasSection: "alias",
+	}
+	sc.Register(newRemoteGroup(afs, ps))
+	sc.RegisterList("list", "", "")
+	return sc, gfs, afs, params
+}
+
+func TestSubCommander_ExecuteContext_ExpandsUserAliases(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"system": "[alias]\nra = remote add -string3 system\nup = ra -bool3 upstream",
+		"user":   "[alias]\nra = remote add -string3 'user value'\nv = -int1 1 up",
+	})
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		args    string
+		global  *clitest.SimpleFlagSetter
+		command *clitest.SimpleFlagSetter
+		params  []string
+	}{
+		{
+			"ra origin",
+			&clitest.SimpleFlagSetter{Suffix: "1"},
+			&clitest.SimpleFlagSetter{Suffix: "3", String: "user value"},
+			[]string{"origin"},
+		},
+		{
+			"-bool1 v -int3 3",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 1, Bool: true},
+			&clitest.SimpleFlagSetter{Suffix: "3", Int: 3, String: "user value", Bool: true},
+			[]string{"upstream"},
+		},
+		{
+			"r add ra",
+			&clitest.SimpleFlagSetter{Suffix: "1"},
+			&clitest.SimpleFlagSetter{Suffix: "3"},
+			[]string{"ra"},
+		},
+	}
+
+	for i, test := range tests {
+		sc, gfs, afs, params := newAliasSubCommander(dir)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields(test.args),
+		}, i)
+
+		if !reflect.DeepEqual(gfs, test.global) {
+			t.Errorf("%v: global flags = %+v WANT %+v", i, gfs, test.global)
+		}
+		if !reflect.DeepEqual(afs, test.command) {
+			t.Errorf("%v: sub-command flags = %+v WANT %+v", i, afs, test.command)
+		}
+		if !reflect.DeepEqual(*params, test.params) {
+			t.Errorf("%v: params = %q WANT %q", i, *params, test.params)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_UserAliasErrors(t *testing.T) {
+	tests := []struct {
+		user string
+		args string
+		line int
+		err  error
+	}{
+		{"[alias]\na = b\nb = -bool1 a", "a", 0, RecursiveAliasError("a")},
+		{"[alias]\n\nr = remote add", "list", 3, AliasShadowsSubCommandError("r")},
+		{"[alias]\nq = 'unterminated", "q", 2, cli.ErrUnterminatedQuote},
+		{"[alias]\ne = \"\"", "e", 2, ErrEmptyAlias},
+	}
+
+	for i, test := range tests {
+		dir := tempConfigDir(t, map[string]string{"user": test.user})
+		defer os.RemoveAll(dir)
+
+		sc, _, _, _ := newAliasSubCommander(dir)
+		_, _, err := executeContext(sc, nil, strings.Fields(test.args), nil)
+
+		pgae, ok := err.(*ParsingGlobalArgsError)
+		if !ok {
+			t.Errorf("%v: err = %#v", i, err)
+			continue
+		}
+		want := test.err
+		if test.line > 0 {
+			want = &cli.ConfigError{Path: filepath.Join(dir, "user"), Line: test.line, Err: test.err}
+		}
+		if !reflect.DeepEqual(pgae.Err, want) {
+			t.Errorf("%v: err = %v WANT %v", i, pgae.Err, want)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_ListIncludesUserAliases(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"user": "[alias]\nra = remote add\nadd-upstream-remote = ra upstream",
+	})
+	defer os.RemoveAll(dir)
+
+	sc, _, _, _ := newAliasSubCommander(dir)
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"list"},
+		OutString: strings.Join([]string{
+			SubCommandsName + ":",
+			"  list            Prints available " + SubCommandName + "s",
+			"  remote, r       ",
+			"",
+			AliasesName + ":",
+			"  add-upstream-remote    ra upstream",
+			"  ra                     remote add",
+			"",
+		}, "\n"),
+	})
+}
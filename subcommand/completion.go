@@ -0,0 +1,555 @@
+package subcommand
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//Shell names supported by RegisterCompletion.
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+
+	ShellName = "shell"
+
+	//CompleteSubCommandName is the name of the hidden SubCommand registered by
+	//RegisterCompletion that completion scripts execute to complete flag and
+	//parameter values at runtime.
+	CompleteSubCommandName = "__complete"
+)
+
+//Shells are the supported shells of RegisterCompletion in the order they are
+//listed in help output.
+var Shells = []string{Bash, Zsh, Fish}
+
+//RegisterCompletion registers a completion SubCommand that prints a shell completion
+//script for a required shell parameter. The shell must be one of Shells.
+//
+//The script completes sub-command names and aliases (including those of Groups),
+//global flags, and the flags of each SubCommand at the position they are valid in.
+//The script is generated from the SubCommands registered with sc at the time
+//the completion SubCommand is executed. Flags are completed as "-name", or as
+//"-n" and "--name" if GNUOptions is true.
+//
+//Flag and parameter values are completed at runtime by SubCommands that implement
+//cli.Completer. RegisterCompletion also registers a hidden SubCommand named
+//CompleteSubCommandName that the script executes with the words on the command
+//line. It prints the candidates from the Completer of the SubCommand named
+//in those words, one per line. The words are parsed in the same way as when
+//the SubCommand is executed, e.g. with GNUOptions and NegativeNumbers.
+//
+//The SubCommand's name, synopsis, description, and aliases are provided as parameters.
+//If synopsis or description are the empty string, then defaults are used.
+func (sc *SubCommander) RegisterCompletion(name, synopsis, description string, aliases ...string) {
+	if synopsis == "" {
+		synopsis = fmt.Sprintf("Prints a %v completion script", ShellName)
+	}
+	if description == "" {
+		description = fmt.Sprintf(
+			"%v for %v names and options. Supported %vs are %v.",
+			synopsis,
+			SubCommandName,
+			ShellName,
+			strings.Join(Shells, ", "),
+		)
+	}
+
+	sc.Register(
+		&completionSubCommand{
+			sc: sc,
+			SubCommandStruct: &SubCommandStruct{
+				NameValue:        name,
+				AliasesValue:     aliases,
+				SynopsisValue:    synopsis,
+				DescriptionValue: description,
+			},
+		},
+	)
+	sc.Register(
+		&completeSubCommand{
+			sc: sc,
+			SubCommandStruct: &SubCommandStruct{
+				NameValue: CompleteSubCommandName,
+			},
+		},
+	)
+}
+
+type completionSubCommand struct {
+	sc *SubCommander
+
+	shell string
+
+	*SubCommandStruct
+}
+
+func (c *completionSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	params := []*cli.Parameter{
+		{Name: ShellName, Optional: false, Many: false},
+	}
+	usage := fmt.Sprintf("%v is one of %v", FormatParameter(params[0]), strings.Join(Shells, ", "))
+
+	return params, usage
+}
+
+func (c *completionSubCommand) SetParameters(params []string) error {
+	if len(params) > 1 {
+		return cli.ErrTooManyParameters
+	}
+	if len(params) == 0 {
+		return &cli.RequiredParameterNotSetError{
+			Name:      ShellName,
+			Many:      false,
+			Formatted: FormatParameter(&cli.Parameter{Name: ShellName}),
+		}
+	}
+
+	for _, shell := range Shells {
+		if params[0] == shell {
+			c.shell = shell
+			return nil
+		}
+	}
+	return UnknownShellError(params[0])
+}
+
+func (c *completionSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+	nodes := c.sc.getCompletionNodes()
+	name := filepath.Base(c.sc.CommandName)
+
+	switch c.shell {
+	case Bash:
+		writeBashCompletion(out, name, nodes)
+	case Zsh:
+		writeZshCompletion(out, name, nodes)
+	case Fish:
+		writeFishCompletion(out, name, nodes)
+	}
+	return nil
+}
+
+type completeSubCommand struct {
+	sc *SubCommander
+
+	words []string
+
+	*SubCommandStruct
+}
+
+func (c *completeSubCommand) hidden() bool {
+	return true
+}
+
+func (c *completeSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return nil, ""
+}
+
+func (c *completeSubCommand) SetParameters(params []string) error {
+	c.words = params
+	return nil
+}
+
+func (c *completeSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+	for _, candidate := range c.sc.complete(c.words) {
+		fmt.Fprintln(out, candidate)
+	}
+	return nil
+}
+
+//isCompleteArgs returns whether or not args execute the SubCommand named
+//CompleteSubCommandName registered by RegisterCompletion.
+func (sc *SubCommander) isCompleteArgs(args []string) bool {
+	if len(args) == 0 || args[0] != CompleteSubCommandName {
+		return false
+	}
+	_, ok := sc.getSubCommand(CompleteSubCommandName).(*completeSubCommand)
+	return ok
+}
+
+//complete returns the completion candidates for the last of words by resolving
+//the SubCommand named in words and calling it if it implements cli.Completer.
+//Words are the program arguments excluding the program name.
+func (sc *SubCommander) complete(words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	args, word := words[:len(words)-1], words[len(words)-1]
+
+	f := cli.NewFlagSet("", sc.getGlobalFlagSetter(nil))
+	if err := sc.parseFlags(f, args); err != nil {
+		return nil
+	}
+
+	args = f.Args()
+	if len(args) == 0 {
+		return nil
+	}
+	subCommand := sc.getSubCommand(args[0])
+	if subCommand == nil {
+		return nil
+	}
+	args = args[1:]
+
+	if sc.DisallowGlobalFlagsWithSubCommand {
+		f = cli.NewFlagSet(subCommand.Name(), nil)
+	}
+
+	path := []SubCommand{subCommand}
+	for _, ok := subCommand.(*Group); ok; _, ok = subCommand.(*Group) {
+		var err error
+		subCommand, args, err = sc.parseGroupArgs(path, f, args, newFlagSources(nil, cli.NewProvenance()))
+		if err != nil {
+			return nil
+		}
+		path = append(path, subCommand)
+	}
+
+	completer, ok := subCommand.(cli.Completer)
+	if !ok {
+		return nil
+	}
+	subCommand.SetFlags(f)
+
+	return cli.Complete(completer, f, args, word, sc.getParseOptions(subCommand))
+}
+
+//completionNode is a position in a SubCommander's tree of SubCommands that
+//completion scripts can be at.
+type completionNode struct {
+	//path is the "/" joined names of the SubCommands leading to this node.
+	//It is the empty string for the root.
+	path string
+
+	//words are the sub-command names, aliases, and flags to complete at this node.
+	words []string
+
+	//valueFlags are the flags at this node that consume the following argument.
+	valueFlags []string
+
+	//shortValueFlags are the GNU short options in valueFlags without their "-",
+	//which also consume the following argument when last in a cluster, e.g. "-vo".
+	shortValueFlags []string
+
+	//shortBoolFlags are the GNU short boolean options without their "-", which may
+	//precede one of shortValueFlags in a cluster.
+	shortBoolFlags []string
+
+	//children maps sub-command names and aliases to their node's path.
+	children [][2]string
+
+	//leaf denotes whether or not this node is a non-Group SubCommand.
+	leaf bool
+}
+
+//flagSetters is a FlagSetter that sets the flags of each non-nil FlagSetter.
+type flagSetters []cli.FlagSetter
+
+func (fss flagSetters) SetFlags(f *flag.FlagSet) {
+	for _, fs := range fss {
+		if fs != nil {
+			fs.SetFlags(f)
+		}
+	}
+}
+
+func (sc *SubCommander) getCompletionNodes() []*completionNode {
+	return sc.appendCompletionNodes(nil, "", &sc.registry, flagSetters{sc.getGlobalFlagSetter(nil)})
+}
+
+func (sc *SubCommander) appendCompletionNodes(nodes []*completionNode, path string, r *registry, fss flagSetters) []*completionNode {
+	node := sc.newCompletionNode(path, fss)
+	nodes = append(nodes, node)
+	if r == nil {
+		node.leaf = true
+		return nodes
+	}
+
+	childFlagSetters := fss
+	if path == "" && sc.DisallowGlobalFlagsWithSubCommand {
+		childFlagSetters = flagSetters{}
+	}
+
+	subCommandWords := []string{}
+	for _, name := range r.sortedSubCommandNames() {
+		subCommand := r.names[name]
+		childPath := path + "/" + name
+
+		for _, word := range append([]string{name}, subCommand.Aliases()...) {
+			node.children = append(node.children, [2]string{word, childPath})
+			subCommandWords = append(subCommandWords, word)
+		}
+
+		childFlagSetters := append(append(flagSetters{}, childFlagSetters...), subCommand)
+		var childRegistry *registry
+		if group, ok := subCommand.(*Group); ok {
+			childRegistry = &group.registry
+		}
+		nodes = sc.appendCompletionNodes(nodes, childPath, childRegistry, childFlagSetters)
+	}
+	node.words = append(subCommandWords, node.words...)
+
+	return nodes
+}
+
+//newCompletionNode returns the completionNode at path with the flags of fs.
+//The flags are named "-name", or "-n" and "--name" if sc.GNUOptions is true.
+func (sc *SubCommander) newCompletionNode(path string, fs cli.FlagSetter) *completionNode {
+	node := &completionNode{path: path}
+	cli.NewFlagSet("", fs).VisitAll(func(fl *flag.Flag) {
+		name := "-" + fl.Name
+		if sc.GNUOptions && len(fl.Name) > 1 {
+			name = "--" + fl.Name
+		}
+		node.words = append(node.words, name)
+		if sc.GNUOptions && len(fl.Name) == 1 && cli.IsBoolFlag(fl) {
+			node.shortBoolFlags = append(node.shortBoolFlags, fl.Name)
+		}
+		if !cli.IsBoolFlag(fl) && !cli.IsOptionalValueFlag(fl) {
+			node.valueFlags = append(node.valueFlags, name)
+			if sc.GNUOptions && len(fl.Name) == 1 {
+				node.shortValueFlags = append(node.shortValueFlags, fl.Name)
+			}
+		}
+	})
+	return node
+}
+
+var nonIdentifierRegexp = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+//completionFuncName returns a shell function name for the command name.
+func completionFuncName(name string) string {
+	return "_" + nonIdentifierRegexp.ReplaceAllString(name, "_") + "_completion"
+}
+
+//shellQuote single quotes value for use in bash, zsh, and fish scripts.
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+func shellQuoteAll(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, shellQuote(value))
+	}
+	return strings.Join(quoted, " ")
+}
+
+//writeShWalk writes the bash and zsh loop body that walks the words before the
+//cursor, skipping flag values and descending into sub-commands.
+func writeShWalk(out io.Writer, nodes []*completionNode, indent string) {
+	fmt.Fprintf(out, "%sif [ \"$skip\" = 1 ]; then\n%s\tskip=0\n%s\tcontinue\n%sfi\n", indent, indent, indent, indent)
+
+	fmt.Fprintf(out, "%scase \"$cmdpath:$word\" in\n", indent)
+	for _, node := range nodes {
+		for _, valueFlag := range node.valueFlags {
+			fmt.Fprintf(out, "%s%s) skip=1 ;;\n", indent, shellQuote(node.path+":"+valueFlag))
+		}
+	}
+	fmt.Fprintf(out, "%sesac\n", indent)
+	writeShClusterWalk(out, nodes, indent)
+
+	fmt.Fprintf(out, "%scase \"$cmdpath/$word\" in\n", indent)
+	for _, node := range nodes {
+		for _, child := range node.children {
+			fmt.Fprintf(out, "%s%s) cmdpath=%s ;;\n", indent, shellQuote(node.path+"/"+child[0]), shellQuote(child[1]))
+		}
+	}
+	fmt.Fprintf(out, "%sesac\n", indent)
+}
+
+//writeShClusterWalk writes the bash and zsh code that skips the argument after a
+//cluster of GNU short options, e.g. "-vo", whose last option is a value flag and
+//all others are boolean flags. Nothing is written if there are no such flags.
+func writeShClusterWalk(out io.Writer, nodes []*completionNode, indent string) {
+	if !hasShortValueFlags(nodes) {
+		return
+	}
+	fmt.Fprintf(out, "%scase \"$word\" in\n", indent)
+	fmt.Fprintf(out, "%s--*) ;;\n", indent)
+	fmt.Fprintf(out, "%s-*)\n", indent)
+	fmt.Fprintf(out, "%s\tbools='' values=''\n", indent)
+	fmt.Fprintf(out, "%s\tcase \"$cmdpath\" in\n", indent)
+	for _, node := range nodes {
+		if len(node.shortValueFlags) > 0 {
+			fmt.Fprintf(
+				out,
+				"%s\t%s) bools=%s values=%s ;;\n",
+				indent,
+				shellQuote(node.path),
+				shellQuote(strings.Join(node.shortBoolFlags, "")),
+				shellQuote(strings.Join(node.shortValueFlags, "")),
+			)
+		}
+	}
+	fmt.Fprintf(out, "%s\tesac\n", indent)
+	fmt.Fprintf(out, "%s\trest=\"${word#-}\"\n", indent)
+	fmt.Fprintf(out, "%s\twhile [ -n \"$rest\" ]; do\n", indent)
+	fmt.Fprintf(out, "%s\t\tc=\"${rest%%\"${rest#?}\"}\"\n", indent)
+	fmt.Fprintf(out, "%s\t\trest=\"${rest#?}\"\n", indent)
+	fmt.Fprintf(out, "%s\t\tcase \"$bools\" in *\"$c\"*) continue ;; esac\n", indent)
+	fmt.Fprintf(out, "%s\t\tcase \"$values\" in *\"$c\"*) [ -z \"$rest\" ] && skip=1 ;; esac\n", indent)
+	fmt.Fprintf(out, "%s\t\tbreak\n", indent)
+	fmt.Fprintf(out, "%s\tdone\n", indent)
+	fmt.Fprintf(out, "%s\t;;\n", indent)
+	fmt.Fprintf(out, "%sesac\n", indent)
+}
+
+//hasShortValueFlags returns whether or not any of nodes has shortValueFlags.
+func hasShortValueFlags(nodes []*completionNode) bool {
+	for _, node := range nodes {
+		if len(node.shortValueFlags) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func writeBashCompletion(out io.Writer, name string, nodes []*completionNode) {
+	funcName := completionFuncName(name)
+
+	fmt.Fprintf(out, "# bash completion for %s\n", name)
+	fmt.Fprintf(out, "%s() {\n", funcName)
+	fmt.Fprintf(out, "\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" cmdpath=\"\" skip=0 word i words=\"\" bools values rest c\n")
+	fmt.Fprintf(out, "\tfor ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(out, "\t\tword=\"${COMP_WORDS[i]}\"\n")
+	writeShWalk(out, nodes, "\t\t")
+	fmt.Fprintf(out, "\tdone\n")
+
+	fmt.Fprintf(out, "\tif [ \"$skip\" = 0 ]; then\n")
+	fmt.Fprintf(out, "\t\tcase \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(out, "\t\t%s) words=%s ;;\n", shellQuote(node.path), shellQuote(strings.Join(node.words, " ")))
+	}
+	fmt.Fprintf(out, "\t\tesac\n")
+	fmt.Fprintf(out, "\tfi\n")
+
+	fmt.Fprintf(out, "\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	fmt.Fprintf(out, "\tlocal IFS=$'\\n'\n")
+	fmt.Fprintf(out, "\tCOMPREPLY+=($(\"${COMP_WORDS[0]}\" %s -- \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null))\n", CompleteSubCommandName)
+	fmt.Fprintf(out, "}\n")
+	fmt.Fprintf(out, "complete -o default -F %s %s\n", funcName, name)
+}
+
+func writeZshCompletion(out io.Writer, name string, nodes []*completionNode) {
+	funcName := completionFuncName(name)
+
+	fmt.Fprintf(out, "#compdef %s\n", name)
+	fmt.Fprintf(out, "%s() {\n", funcName)
+	fmt.Fprintf(out, "\tlocal cmdpath=\"\" skip=0 word i bools values rest c\n")
+	fmt.Fprintf(out, "\tlocal -a values\n")
+	fmt.Fprintf(out, "\tfor ((i = 2; i < CURRENT; i++)); do\n")
+	fmt.Fprintf(out, "\t\tword=\"${words[i]}\"\n")
+	writeShWalk(out, nodes, "\t\t")
+	fmt.Fprintf(out, "\tdone\n")
+
+	fmt.Fprintf(out, "\tvalues=(${(f)\"$(\"${words[1]}\" %s -- \"${(@)words[2,CURRENT]}\" 2>/dev/null)\"})\n", CompleteSubCommandName)
+	fmt.Fprintf(out, "\tif (( ${#values} )); then\n")
+	fmt.Fprintf(out, "\t\tcompadd -- \"${values[@]}\"\n")
+	fmt.Fprintf(out, "\tfi\n")
+
+	fmt.Fprintf(out, "\tif [ \"$skip\" = 1 ]; then\n")
+	fmt.Fprintf(out, "\t\treturn\n")
+	fmt.Fprintf(out, "\tfi\n")
+
+	fmt.Fprintf(out, "\tcase \"$cmdpath\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(out, "\t\t%s)\n", shellQuote(node.path))
+		if len(node.words) > 0 {
+			fmt.Fprintf(out, "\t\t\tcompadd -- %s\n", shellQuoteAll(node.words))
+		}
+		if node.leaf {
+			fmt.Fprintf(out, "\t\t\t_files\n")
+		}
+		fmt.Fprintf(out, "\t\t\t;;\n")
+	}
+	fmt.Fprintf(out, "\tesac\n")
+
+	fmt.Fprintf(out, "}\n")
+	fmt.Fprintf(out, "compdef %s %s\n", funcName, name)
+}
+
+//writeFishClusterWalk is the fish equivalent of writeShClusterWalk.
+func writeFishClusterWalk(out io.Writer, nodes []*completionNode) {
+	if !hasShortValueFlags(nodes) {
+		return
+	}
+	fmt.Fprintf(out, "\t\tswitch $word\n")
+	fmt.Fprintf(out, "\t\t\tcase '--*'\n")
+	fmt.Fprintf(out, "\t\t\tcase '-*'\n")
+	fmt.Fprintf(out, "\t\t\t\tset -l bools ''\n")
+	fmt.Fprintf(out, "\t\t\t\tset -l values ''\n")
+	fmt.Fprintf(out, "\t\t\t\tswitch \"$cmdpath\"\n")
+	for _, node := range nodes {
+		if len(node.shortValueFlags) > 0 {
+			fmt.Fprintf(
+				out,
+				"\t\t\t\t\tcase %s\n\t\t\t\t\t\tset bools %s\n\t\t\t\t\t\tset values %s\n",
+				shellQuote(node.path),
+				shellQuote(strings.Join(node.shortBoolFlags, "")),
+				shellQuote(strings.Join(node.shortValueFlags, "")),
+			)
+		}
+	}
+	fmt.Fprintf(out, "\t\t\t\tend\n")
+	fmt.Fprintf(out, "\t\t\t\tset -l rest (string sub -s 2 -- $word)\n")
+	fmt.Fprintf(out, "\t\t\t\twhile test -n \"$rest\"\n")
+	fmt.Fprintf(out, "\t\t\t\t\tset -l c (string sub -l 1 -- $rest)\n")
+	fmt.Fprintf(out, "\t\t\t\t\tset rest (string sub -s 2 -- $rest)\n")
+	fmt.Fprintf(out, "\t\t\t\t\tif contains -- $c (string split '' -- $bools)\n\t\t\t\t\t\tcontinue\n\t\t\t\t\tend\n")
+	fmt.Fprintf(out, "\t\t\t\t\tif contains -- $c (string split '' -- $values); and test -z \"$rest\"\n\t\t\t\t\t\tset skip 1\n\t\t\t\t\tend\n")
+	fmt.Fprintf(out, "\t\t\t\t\tbreak\n")
+	fmt.Fprintf(out, "\t\t\t\tend\n")
+	fmt.Fprintf(out, "\t\tend\n")
+}
+
+func writeFishCompletion(out io.Writer, name string, nodes []*completionNode) {
+	funcName := completionFuncName(name)
+
+	fmt.Fprintf(out, "# fish completion for %s\n", name)
+	fmt.Fprintf(out, "function %s\n", funcName)
+	fmt.Fprintf(out, "\tset -l tokens (commandline -opc)\n")
+	fmt.Fprintf(out, "\tset -l cmdpath ''\n")
+	fmt.Fprintf(out, "\tset -l skip 0\n")
+	fmt.Fprintf(out, "\tfor word in $tokens[2..-1]\n")
+	fmt.Fprintf(out, "\t\tif test $skip = 1\n\t\t\tset skip 0\n\t\t\tcontinue\n\t\tend\n")
+
+	fmt.Fprintf(out, "\t\tswitch \"$cmdpath:$word\"\n")
+	for _, node := range nodes {
+		for _, valueFlag := range node.valueFlags {
+			fmt.Fprintf(out, "\t\t\tcase %s\n\t\t\t\tset skip 1\n", shellQuote(node.path+":"+valueFlag))
+		}
+	}
+	fmt.Fprintf(out, "\t\tend\n")
+	writeFishClusterWalk(out, nodes)
+
+	fmt.Fprintf(out, "\t\tswitch \"$cmdpath/$word\"\n")
+	for _, node := range nodes {
+		for _, child := range node.children {
+			fmt.Fprintf(out, "\t\t\tcase %s\n\t\t\t\tset cmdpath %s\n", shellQuote(node.path+"/"+child[0]), shellQuote(child[1]))
+		}
+	}
+	fmt.Fprintf(out, "\t\tend\n")
+	fmt.Fprintf(out, "\tend\n")
+
+	fmt.Fprintf(out, "\t$tokens[1] %s -- $tokens[2..-1] (commandline -ct) 2>/dev/null\n", CompleteSubCommandName)
+	fmt.Fprintf(out, "\tif test $skip = 1\n\t\treturn\n\tend\n")
+
+	fmt.Fprintf(out, "\tswitch \"$cmdpath\"\n")
+	for _, node := range nodes {
+		fmt.Fprintf(out, "\t\tcase %s\n", shellQuote(node.path))
+		if len(node.words) > 0 {
+			fmt.Fprintf(out, "\t\t\tprintf '%%s\\n' %s\n", shellQuoteAll(node.words))
+		}
+	}
+	fmt.Fprintf(out, "\tend\n")
+	fmt.Fprintf(out, "end\n")
+	fmt.Fprintf(out, "complete -c %s -a '(%s)'\n", name, funcName)
+}
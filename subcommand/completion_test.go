@@ -0,0 +1,384 @@
+package subcommand
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestSubCommander_RegisterCompletion_RegistersWithNameAndAliases(t *testing.T) {
+	sc := &SubCommander{}
+
+	sc.RegisterCompletion("completion", "", "", "comp")
+
+	if sc.names["completion"] == nil {
+		t.Fatalf("completion should be registered")
+	}
+	if sc.aliases["comp"] == nil {
+		t.Fatalf("comp should be registered")
+	}
+}
+
+func TestSubCommander_ExecuteContext_SubCommandRegisteredCompletionErrorsWithUnknownShell(t *testing.T) {
+	sc := &SubCommander{}
+	sc.RegisterCompletion("completion", "", "")
+	err := UnknownShellError("csh")
+
+	sct := &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("completion csh"),
+		OutErrString: err.Error() + "\n\n" + Usage + " ... completion [parameters...]" + "\n\n" +
+			ParametersName + ": <SHELL>" + "\n" + "<SHELL> is one of bash, zsh, fish" + "\n",
+		Err: &ParsingSubCommandError{err},
+	}
+
+	testSubCommanderTest(t, sct)
+}
+
+func TestSubCommander_ExecuteContext_SubCommandRegisteredCompletionPrintsScripts(t *testing.T) {
+	tests := []struct {
+		shell    string
+		contains []string
+	}{
+		{
+			Bash,
+			[]string{
+				"_prog_completion() {",
+				"'/remote:-r1') skip=1 ;;",
+				"'/r') cmdpath='/remote' ;;",
+				"'') words='completion remote r -g1' ;;",
+				"'/remote/add') words='-a1 -g1 -r1' ;;",
+				"complete -o default -F _prog_completion prog",
+			},
+		},
+		{
+			Zsh,
+			[]string{
+				"#compdef prog",
+				"'/remote:-r1') skip=1 ;;",
+				"compadd -- 'add' '-g1' '-r1'",
+				"compdef _prog_completion prog",
+			},
+		},
+		{
+			Fish,
+			[]string{
+				"function _prog_completion",
+				"case '/remote:-r1'\n\t\t\t\tset skip 1",
+				"case '/r'\n\t\t\t\tset cmdpath '/remote'",
+				"printf '%s\\n' 'add' '-g1' '-r1'",
+				"complete -c prog -a '(_prog_completion)'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		remote := &Group{
+			NameValue:    "remote",
+			AliasesValue: []string{"r"},
+			FlagSetter:   clitest.NewStringsFlagSetter("r1"),
+		}
+		remote.Register(&SubCommandStruct{
+			NameValue:  "add",
+			FlagSetter: clitest.NewStringsFlagSetter("a1"),
+		})
+
+		sc := &SubCommander{
+			CommandName: "/usr/local/bin/prog",
+			GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+		}
+		sc.Register(remote)
+		sc.RegisterCompletion("completion", "", "")
+
+		out, outErr, err := executeContext(sc, nil, []string{"completion", test.shell}, nil)
+		if err != nil || outErr.Len() != 0 {
+			t.Fatalf("%v: err = %v outErr = %v", test.shell, err, outErr)
+		}
+		for _, contains := range test.contains {
+			if !strings.Contains(out.String(), contains) {
+				t.Errorf("%v: script does not contain %q\n%v", test.shell, contains, out)
+			}
+		}
+	}
+}
+
+func TestSubCommander_getCompletionNodes_DisallowGlobalFlagsWithSubCommand(t *testing.T) {
+	sc := &SubCommander{
+		GlobalFlags:                       &clitest.SimpleFlagSetter{},
+		DisallowGlobalFlagsWithSubCommand: true,
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue:    "sub",
+		AliasesValue: []string{"s"},
+		FlagSetter:   clitest.NewStringsFlagSetter("s1"),
+	})
+
+	nodes := sc.getCompletionNodes()
+
+	want := []*completionNode{
+		{
+			path:       "",
+			words:      []string{"sub", "s", "-bool", "-int", "-string"},
+			valueFlags: []string{"-int", "-string"},
+			children:   [][2]string{{"sub", "/sub"}, {"s", "/sub"}},
+		},
+		{
+			path:       "/sub",
+			words:      []string{"-s1"},
+			valueFlags: []string{"-s1"},
+			leaf:       true,
+		},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Fatalf("getCompletionNodes() = %v WANT %v", nodes, want)
+	}
+}
+
+func TestSubCommander_getCompletionNodes_GNUOptions(t *testing.T) {
+	output, verbose := "", false
+	sc := &SubCommander{
+		GNUOptions: true,
+		GlobalFlags: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+			f.BoolVar(&verbose, "v", false, "")
+			f.BoolVar(&verbose, "verbose", false, "")
+			f.StringVar(&output, "o", "", "")
+			f.StringVar(&output, "output", "", "")
+		}),
+	}
+	sc.Register(&SubCommandStruct{NameValue: "show"})
+
+	nodes := sc.getCompletionNodes()
+
+	want := &completionNode{
+		path:            "",
+		words:           []string{"show", "-o", "--output", "-v", "--verbose"},
+		valueFlags:      []string{"-o", "--output"},
+		shortValueFlags: []string{"o"},
+		shortBoolFlags:  []string{"v"},
+		children:        [][2]string{{"show", "/show"}},
+	}
+	if !reflect.DeepEqual(nodes[0], want) {
+		t.Fatalf("getCompletionNodes()[0] = %+v WANT %+v", nodes[0], want)
+	}
+
+	for shell, contains := range map[string][]string{
+		Bash: {"':--output') skip=1 ;;", "\t\t\t'') bools='v' values='o' ;;\n"},
+		Zsh:  {"compadd -- 'show' '-o' '--output' '-v' '--verbose'"},
+		Fish: {"case ''\n\t\t\t\t\t\tset bools 'v'\n\t\t\t\t\t\tset values 'o'\n"},
+	} {
+		buf := &bytes.Buffer{}
+		switch shell {
+		case Bash:
+			writeBashCompletion(buf, "prog", nodes)
+		case Zsh:
+			writeZshCompletion(buf, "prog", nodes)
+		case Fish:
+			writeFishCompletion(buf, "prog", nodes)
+		}
+		for _, c := range contains {
+			if !strings.Contains(buf.String(), c) {
+				t.Errorf("%v: script does not contain %q\n%v", shell, c, buf)
+			}
+		}
+	}
+}
+
+func TestWriteShClusterWalk_SkipsOnlyAfterValueFlagLastInCluster(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip(err)
+	}
+	nodes := []*completionNode{
+		{shortValueFlags: []string{"o"}, shortBoolFlags: []string{"v", "x"}},
+	}
+
+	for _, test := range []struct {
+		word string
+		skip string
+	}{
+		{"-o", "1"},
+		{"-vo", "1"},
+		{"-xvo", "1"},
+		{"-ofoo", "0"},
+		{"-vofoo", "0"},
+		{"-ov", "0"},
+		{"-ao", "0"},
+		{"--o", "0"},
+		{"-", "0"},
+		{"o", "0"},
+	} {
+		script := &bytes.Buffer{}
+		fmt.Fprintf(script, "cmdpath='' skip=0 word=%s\n", shellQuote(test.word))
+		writeShClusterWalk(script, nodes, "")
+		fmt.Fprintf(script, "printf %%s \"$skip\"\n")
+
+		out, err := exec.Command(bash, "-c", script.String()).CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %v %s", test.word, err, out)
+		}
+		if string(out) != test.skip {
+			t.Errorf("%v: skip = %s WANT %s", test.word, out, test.skip)
+		}
+	}
+}
+
+func TestFlagSetters_SetFlags_IgnoresNil(t *testing.T) {
+	f := cli.NewFlagSet("", flagSetters{nil, clitest.NewStringsFlagSetter("a")})
+
+	if count := cli.CountFlags(f); count != 1 {
+		t.Fatalf("CountFlags() = %v WANT %v", count, 1)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if result := shellQuote("it's"); result != `'it'\''s'` {
+		t.Fatal(result)
+	}
+}
+
+func TestSubCommander_ExecuteContext_SubCommandRegisteredCompletePrintsCompleterCandidates(t *testing.T) {
+	remote := &Group{
+		NameValue:  "remote",
+		FlagSetter: clitest.NewStringsFlagSetter("r1"),
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:  "add",
+		FlagSetter: clitest.NewStringsFlagSetter("format"),
+		CompleteValue: func(c *cli.Completion) []string {
+			switch c.Flag {
+			case "format":
+				return []string{"json", "text"}
+			case "r1":
+				return []string{"inherited"}
+			}
+			return []string{"origin", "upstream"}
+		},
+	})
+
+	tests := []struct {
+		words     string
+		outString string
+	}{
+		{"-g1 value remote add ", "origin\nupstream\n"},
+		{"remote -r1 value add u", "upstream\n"},
+		{"remote add -format ", "json\ntext\n"},
+		{"remote add -r1 ", "inherited\n"},
+		{"remote add -", ""},
+		{"remote ", ""},
+		{"unknown ", ""},
+	}
+
+	for i, test := range tests {
+		sc := &SubCommander{
+			GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+		}
+		sc.Register(remote)
+		sc.RegisterCompletion("completion", "", "")
+
+		args := append([]string{CompleteSubCommandName, "--"}, strings.Split(test.words, " ")...)
+
+		sct := &SubCommanderTest{
+			SubCommander: sc,
+			Args:         args,
+			OutString:    test.outString,
+		}
+
+		testSubCommanderTest(t, sct, i)
+	}
+}
+
+func TestSubCommander_ExecuteContext_CompleteParsesLikeExecution(t *testing.T) {
+	//Candidates must begin with the word being completed.
+	completeValue := func(c *cli.Completion) []string {
+		if len(c.Flag) > 0 {
+			return []string{c.Word + ":flag " + c.Flag}
+		}
+		return []string{fmt.Sprintf("%v:parameter %v", c.Word, len(c.Parameters))}
+	}
+	newSubCommand := func(stop bool) *SubCommandStruct {
+		output, verbose := "", false
+		return &SubCommandStruct{
+			NameValue: "show",
+			FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				f.BoolVar(&verbose, "v", false, "")
+				f.BoolVar(&verbose, "verbose", false, "")
+				f.StringVar(&output, "o", "", "")
+				f.StringVar(&output, "output", "", "")
+			}),
+			StopAtFirstParameterValue: stop,
+			CompleteValue:             completeValue,
+		}
+	}
+
+	tests := []struct {
+		gnu       bool
+		stop      bool
+		numbers   bool
+		words     []string
+		outString string
+	}{
+		{true, false, false, []string{"show", "-vo", ""}, ":flag o\n"},
+		{true, false, false, []string{"show", "--out", ""}, ":flag output\n"},
+		{true, false, false, []string{"show", "-vojs"}, "-vojs:flag o\n"},
+		{true, false, false, []string{"show", "-vo"}, ""},
+		{true, false, false, []string{"show", "-vojs", ""}, ":parameter 0\n"},
+		{false, false, false, []string{"show", "--output", ""}, ":flag output\n"},
+		{false, false, false, []string{"show", "a", "-o", ""}, ":flag o\n"},
+		{false, true, false, []string{"show", "-o", ""}, ":flag o\n"},
+		{false, true, false, []string{"show", "a", "-o", ""}, ":parameter 2\n"},
+		{true, true, false, []string{"show", "a", "-v"}, "-v:parameter 1\n"},
+		{false, false, true, []string{"show", "-5", "-2"}, "-2:parameter 1\n"},
+		{false, false, false, []string{"show", "-5"}, ""},
+	}
+
+	for i, test := range tests {
+		sc := &SubCommander{
+			GNUOptions:      test.gnu,
+			NegativeNumbers: test.numbers,
+		}
+		sc.Register(newSubCommand(test.stop))
+		sc.RegisterCompletion("completion", "", "")
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         append([]string{CompleteSubCommandName, "--"}, test.words...),
+			OutString:    test.outString,
+		}, i)
+	}
+}
+
+func TestSubCommander_ExecuteContext_CompleteDoesNotExpandResponseFiles(t *testing.T) {
+	sc := &SubCommander{ResponseFiles: true}
+	sc.Register(&SubCommandStruct{
+		NameValue: "show",
+		CompleteValue: func(c *cli.Completion) []string {
+			return []string{c.Word + "le"}
+		},
+	})
+	sc.RegisterCompletion("completion", "", "")
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{CompleteSubCommandName, "show", "@missing", "@fi"},
+		OutString:    "@file\n",
+	})
+}
+
+func TestSubCommander_RegisterCompletion_CompleteSubCommandIsHidden(t *testing.T) {
+	sc := &SubCommander{}
+	sc.RegisterCompletion("completion", "", "")
+
+	if usage := sc.getAvailableSubCommandsUsage(); strings.Contains(usage, CompleteSubCommandName) {
+		t.Fatalf("%v should be hidden in %q", CompleteSubCommandName, usage)
+	}
+	if sc.getSubCommand(CompleteSubCommandName) == nil {
+		t.Fatalf("%v should be registered", CompleteSubCommandName)
+	}
+}
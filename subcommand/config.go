@@ -0,0 +1,152 @@
+package subcommand
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//flagSources records, while the flags of a FlagSet are set, how the flags that
+//are not present in the arguments may be set from the environment and configuration
+//files once the arguments are parsed.
+type flagSources struct {
+	//envNames holds environment variable names by flag name.
+	envNames map[string]string
+
+	//configs are the configuration files in order of decreasing precedence.
+	configs []*cli.Config
+
+	//sections are the configuration sections of the flags in the FlagSet.
+	sections []*configSection
+
+	//provenance records the sources of the values of the flags.
+	//It is shared by the FlagSets of a single execution.
+	provenance *cli.Provenance
+
+	//profile is the active profile of the execution.
+	profile string
+
+	//globals is the FlagSet of the global flags if it is not the FlagSet of the
+	//SubCommand's flags, i.e. if DisallowGlobalFlagsWithSubCommand is true.
+	globals *flag.FlagSet
+}
+
+//configSection is a configuration section and the names of the flags that may
+//be set from it.
+type configSection struct {
+	name  string
+	flags []string
+}
+
+func newFlagSources(configs []*cli.Config, p *cli.Provenance) *flagSources {
+	return &flagSources{
+		envNames:   map[string]string{},
+		configs:    configs,
+		provenance: p,
+	}
+}
+
+//setFlags calls fs.SetFlags(f), if fs is not nil, and records the flags it sets
+//as belonging to the configuration section named section.
+//The flags are also added to s.provenance so that its Options are ordered by
+//section.
+func (s *flagSources) setFlags(f *flag.FlagSet, fs cli.FlagSetter, section string) {
+	existing := map[string]bool{}
+	f.VisitAll(func(fl *flag.Flag) {
+		existing[fl.Name] = true
+	})
+
+	if fs != nil {
+		fs.SetFlags(f)
+	}
+
+	cs := &configSection{name: section, flags: []string{}}
+	f.VisitAll(func(fl *flag.Flag) {
+		if !existing[fl.Name] {
+			cs.flags = append(cs.flags, fl.Name)
+		}
+	})
+	s.sections = append(s.sections, cs)
+	s.provenance.AddFlags(f)
+}
+
+//setFlagsFromSources sets the flags in f that were not present in the arguments
+//from the environment and then from s.configs. The sections of the active profile
+//take precedence over all other sections.
+//It must be called once f is parsed so that s.provenance records the flags set
+//in the arguments.
+func (sc *SubCommander) setFlagsFromSources(f *flag.FlagSet, s *flagSources) error {
+	s.provenance.AddFlags(f)
+	if err := cli.SetFlagsFromEnv(f, s.envNames, sc.LookupEnv, s.provenance); err != nil {
+		return err
+	}
+	if err := sc.setProfile(f, s); err != nil {
+		return err
+	}
+	for _, cs := range s.sections {
+		if len(s.profile) > 0 {
+			err := cli.SetFlagsFromConfig(f, s.configs, getProfileSection(s.profile, cs.name), cs.flags, s.provenance)
+			if err != nil {
+				return err
+			}
+		}
+		if err := cli.SetFlagsFromConfig(f, s.configs, cs.name, cs.flags, s.provenance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//loadConfig loads sc.ConfigFiles and checks that every section names a path of
+//SubCommands, optionally preceded by a profile, or is sc.AliasSection, and that
+//every key in a section names a flag of the section.
+func (sc *SubCommander) loadConfig() ([]*cli.Config, error) {
+	configs, err := sc.ConfigFiles.Load()
+	if err != nil {
+		return nil, err
+	}
+	err = cli.CheckConfigSections(configs, func(name string) bool {
+		if len(sc.AliasSection) > 0 && name == sc.AliasSection {
+			return true
+		}
+		if _, section, ok := sc.splitProfileSection(name); ok {
+			name = section
+		}
+		_, err := sc.getSubCommandPath(strings.Fields(name))
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := cli.CheckConfigKeys(configs, sc.getConfigSectionFlagSet); err != nil {
+		return nil, err
+	}
+	return configs, sc.checkUserAliases(configs)
+}
+
+//getConfigSectionFlagSet returns a FlagSet with the flags that may be set from
+//the configuration section named name, or nil if name is sc.AliasSection or does
+//not name a path of SubCommands.
+func (sc *SubCommander) getConfigSectionFlagSet(name string) *flag.FlagSet {
+	if len(sc.AliasSection) > 0 && name == sc.AliasSection {
+		return nil
+	}
+	if _, section, ok := sc.splitProfileSection(name); ok {
+		name = section
+	}
+	if len(name) == 0 {
+		return sc.globalFlagSet()
+	}
+	path, err := sc.getSubCommandPath(strings.Fields(name))
+	if err != nil {
+		return nil
+	}
+	return cli.NewFlagSet("", path[len(path)-1])
+}
+
+//getConfigSection returns the name of the configuration section for the SubCommand
+//at the end of path, e.g. "remote add".
+func getConfigSection(path []SubCommand) string {
+	return strings.Join(getPathNames(path), " ")
+}
@@ -0,0 +1,379 @@
+package subcommand
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gogolfing/cli"
+)
+
+//Names of the SubCommands and parameters of the Group registered by RegisterConfig.
+const (
+	ConfigGetName  = "get"
+	ConfigSetName  = "set"
+	ConfigListName = "list"
+	ConfigInitName = "init"
+
+	ConfigKeyName   = "key"
+	ConfigValueName = "value"
+)
+
+//RegisterConfig registers a Group of SubCommands that manage the configuration
+//files of sc.ConfigFiles. The SubCommands are generated from the flags of
+//sc.GlobalFlags and each registered SubCommand:
+//	list               prints the effective value and source of each key
+//	get <key>          prints the value of key in the user configuration file
+//	set <key> <value>  sets key to value in the user configuration file
+//	init               writes a commented user configuration file of every key
+//
+//Keys are the names of the Groups and SubCommand of a flag's configuration section
+//and the flag's name joined by ".", e.g. "verbose" for a global flag and
+//"remote.add.name" for the flag -name of "remote add".
+//
+//Keys are resolved when the SubCommands execute, so RegisterConfig may be called
+//before or after the other SubCommands are registered.
+//The SubCommand's name, synopsis, description, and aliases are provided as parameters.
+//If synopsis or description are the empty string, then defaults are used.
+func (sc *SubCommander) RegisterConfig(name, synopsis, description string, aliases ...string) {
+	if synopsis == "" {
+		synopsis = "Manages configuration files"
+	}
+	if description == "" {
+		description = fmt.Sprintf(
+			"%v. Keys are the names of %vs and options joined by \".\".",
+			synopsis,
+			SubCommandName,
+		)
+	}
+
+	g := &Group{
+		NameValue:        name,
+		AliasesValue:     aliases,
+		SynopsisValue:    synopsis,
+		DescriptionValue: description,
+	}
+	g.Register(&configListSubCommand{
+		sc: sc,
+		SubCommandStruct: &SubCommandStruct{
+			NameValue:        ConfigListName,
+			SynopsisValue:    "Prints the effective value of each configuration key",
+			DescriptionValue: "Prints the effective value of each configuration key and where it came from.",
+		},
+	})
+	g.Register(&configGetSubCommand{
+		sc: sc,
+		SubCommandStruct: &SubCommandStruct{
+			NameValue:        ConfigGetName,
+			SynopsisValue:    "Prints the value of a key in the user configuration file",
+			DescriptionValue: "Prints the value of a key in the user configuration file.",
+		},
+	})
+	g.Register(&configSetSubCommand{
+		sc: sc,
+		SubCommandStruct: &SubCommandStruct{
+			NameValue:        ConfigSetName,
+			SynopsisValue:    "Sets the value of a key in the user configuration file",
+			DescriptionValue: "Sets the value of a key in the user configuration file. The value must be valid for the key's option.",
+		},
+	})
+	g.Register(&configInitSubCommand{
+		sc: sc,
+		SubCommandStruct: &SubCommandStruct{
+			NameValue:        ConfigInitName,
+			SynopsisValue:    "Writes a default user configuration file",
+			DescriptionValue: "Writes a user configuration file with every key commented out and described. The file must not already exist.",
+		},
+	})
+
+	sc.Register(g)
+}
+
+type configListSubCommand struct {
+	sc *SubCommander
+
+	*SubCommandStruct
+}
+
+func (c *configListSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return nil, ""
+}
+
+func (c *configListSubCommand) SetParameters(params []string) error {
+	if len(params) > 0 {
+		return cli.ErrTooManyParameters
+	}
+	return nil
+}
+
+func (c *configListSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+	configs, err := c.sc.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%s\t%s\n", "KEY", "VALUE", "SOURCE")
+	for _, path := range c.sc.getConfigPaths() {
+		p := cli.NewProvenance()
+		if _, err := c.sc.getConfigFlagSet(path, newFlagSources(configs, p), true); err != nil {
+			return err
+		}
+		for _, option := range p.Options() {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", getConfigKey(path, option.Flag.Name), option.Flag.Value, option.Source)
+		}
+	}
+	return tw.Flush()
+}
+
+type configGetSubCommand struct {
+	sc *SubCommander
+
+	key string
+
+	*SubCommandStruct
+}
+
+func (c *configGetSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return []*cli.Parameter{{Name: ConfigKeyName}}, ""
+}
+
+func (c *configGetSubCommand) SetParameters(params []string) error {
+	values, err := getConfigParameters(params, ConfigKeyName)
+	if err != nil {
+		return err
+	}
+	c.key = values[0]
+	return nil
+}
+
+func (c *configGetSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+	path, fl, err := c.sc.getConfigKeyFlag(c.key)
+	if err != nil {
+		return err
+	}
+	userPath, err := c.sc.getUserConfigPath()
+	if err != nil {
+		return err
+	}
+	config, err := cli.ReadConfig(userPath)
+	if err != nil {
+		return err
+	}
+
+	if config != nil {
+		if section := config.Section(getConfigSection(path)); section != nil {
+			for i := len(section.Values) - 1; i >= 0; i-- {
+				if section.Values[i].Key == fl.Name {
+					value := section.Values[i].Value
+					if cli.IsSecret(fl) && value != "" {
+						value = cli.SecretMask
+					}
+					fmt.Fprintln(out, value)
+					return nil
+				}
+			}
+		}
+	}
+	return ConfigKeyNotSetError(c.key)
+}
+
+type configSetSubCommand struct {
+	sc *SubCommander
+
+	key   string
+	value string
+
+	*SubCommandStruct
+}
+
+func (c *configSetSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return []*cli.Parameter{{Name: ConfigKeyName}, {Name: ConfigValueName}}, ""
+}
+
+func (c *configSetSubCommand) SetParameters(params []string) error {
+	values, err := getConfigParameters(params, ConfigKeyName, ConfigValueName)
+	if err != nil {
+		return err
+	}
+	c.key, c.value = values[0], values[1]
+	return nil
+}
+
+func (c *configSetSubCommand) Execute(_ context.Context, _ io.Reader, _, _ io.Writer) error {
+	path, fl, err := c.sc.getConfigKeyFlag(c.key)
+	if err != nil {
+		return err
+	}
+	userPath, err := c.sc.getUserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	//Secrets accept any value, and setting one may read a file, so they are not
+	//validated. fl is of a FlagSet of its own, but its Value may be bound to the
+	//state of a SubCommand, so it is reset to its default once validated.
+	if !cli.IsSecret(fl) {
+		err := fl.Value.Set(c.value)
+		fl.Value.Set(fl.DefValue)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %s: %v", c.value, c.key, err)
+		}
+	}
+
+	return cli.SetConfigFileValue(userPath, getConfigSection(path), fl.Name, c.value)
+}
+
+type configInitSubCommand struct {
+	sc *SubCommander
+
+	*SubCommandStruct
+}
+
+func (c *configInitSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return nil, ""
+}
+
+func (c *configInitSubCommand) SetParameters(params []string) error {
+	if len(params) > 0 {
+		return cli.ErrTooManyParameters
+	}
+	return nil
+}
+
+func (c *configInitSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+	userPath, err := c.sc.getUserConfigPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(userPath); err == nil {
+		return &os.PathError{Op: ConfigInitName, Path: userPath, Err: os.ErrExist}
+	}
+
+	content := &bytes.Buffer{}
+	fmt.Fprintf(content, "# Configuration for %s.\n", filepath.Base(c.sc.CommandName))
+	for _, path := range c.sc.getConfigPaths() {
+		f, _ := c.sc.getConfigFlagSet(path, newFlagSources(nil, cli.NewProvenance()), false)
+		if cli.CountFlags(f) == 0 {
+			continue
+		}
+		if len(path) > 0 {
+			fmt.Fprintf(content, "\n[%s]\n", getConfigSection(path))
+		}
+		f.VisitAll(func(fl *flag.Flag) {
+			if len(fl.Usage) > 0 {
+				fmt.Fprintf(content, "\n# %s\n", fl.Usage)
+			}
+			defValue := fl.DefValue
+			if cli.IsSecret(fl) {
+				defValue = ""
+			}
+			fmt.Fprintf(content, "#%s\n", cli.FormatConfigValue(fl.Name, defValue))
+		})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(userPath), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(userPath, content.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, userPath)
+	return nil
+}
+
+//getConfigParameters returns params if there is exactly one for each of names.
+func getConfigParameters(params []string, names ...string) ([]string, error) {
+	if len(params) > len(names) {
+		return nil, cli.ErrTooManyParameters
+	}
+	if len(params) < len(names) {
+		param := &cli.Parameter{Name: names[len(params)]}
+		return nil, &cli.RequiredParameterNotSetError{
+			Name:      param.Name,
+			Formatted: FormatParameter(param),
+		}
+	}
+	return params, nil
+}
+
+//getConfigPaths returns the path of every SubCommand of sc that is not hidden,
+//preceded by the empty path of the global flags.
+func (sc *SubCommander) getConfigPaths() [][]SubCommand {
+	return sc.appendConfigPaths([][]SubCommand{nil}, nil, &sc.registry)
+}
+
+func (sc *SubCommander) appendConfigPaths(paths [][]SubCommand, parent []SubCommand, r *registry) [][]SubCommand {
+	for _, name := range r.sortedSubCommandNames() {
+		subCommand := r.names[name]
+		path := append(append([]SubCommand{}, parent...), subCommand)
+		paths = append(paths, path)
+		if group, ok := subCommand.(*Group); ok {
+			paths = sc.appendConfigPaths(paths, path, &group.registry)
+		}
+	}
+	return paths
+}
+
+//getConfigFlagSet returns a FlagSet of the flags of the configuration section of
+//path, i.e. the global flags for an empty path or the flags of the SubCommand
+//at the end of path otherwise.
+//If apply is true, then the flags are set from the environment and s.configs.
+func (sc *SubCommander) getConfigFlagSet(path []SubCommand, s *flagSources, apply bool) (*flag.FlagSet, error) {
+	f := cli.NewFlagSet("", nil)
+	if len(path) == 0 {
+		s.setFlags(f, sc.getGlobalFlagSetter(s.envNames), "")
+	} else {
+		s.setFlags(f, sc.getSubCommandFlagSetter(path, s.envNames), getConfigSection(path))
+	}
+	if !apply {
+		return f, nil
+	}
+	return f, sc.setFlagsFromSources(f, s)
+}
+
+//getConfigKeyFlag resolves key into the path of the SubCommand whose flag it names
+//and that flag. The flag belongs to a FlagSet of its own.
+func (sc *SubCommander) getConfigKeyFlag(key string) ([]SubCommand, *flag.Flag, error) {
+	parts := strings.Split(key, ".")
+	path := []SubCommand{}
+	for r := &sc.registry; r != nil && len(path) < len(parts)-1; {
+		subCommand := r.getSubCommand(parts[len(path)])
+		if subCommand == nil {
+			break
+		}
+		path = append(path, subCommand)
+
+		r = nil
+		if group, ok := subCommand.(*Group); ok {
+			r = &group.registry
+		}
+	}
+
+	f, _ := sc.getConfigFlagSet(path, newFlagSources(nil, cli.NewProvenance()), false)
+	fl := f.Lookup(strings.Join(parts[len(path):], "."))
+	if fl == nil {
+		return nil, nil, cli.UnknownConfigKeyError(key)
+	}
+	return path, fl, nil
+}
+
+//getConfigKey returns the key of the flag named name of the SubCommand at the
+//end of path.
+func getConfigKey(path []SubCommand, name string) string {
+	return strings.Join(append(getPathNames(path), name), ".")
+}
+
+func (sc *SubCommander) getUserConfigPath() (string, error) {
+	if len(sc.ConfigFiles.User) == 0 {
+		return "", ErrNoUserConfigFile
+	}
+	return sc.ConfigFiles.User, nil
+}
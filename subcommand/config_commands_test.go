@@ -0,0 +1,215 @@
+package subcommand
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func newConfigSubCommander(dir string) *SubCommander {
+	sc := &SubCommander{
+		CommandName: "prog",
+		GlobalFlags: &clitest.SimpleFlagSetter{},
+		EnvPrefix:   "prog",
+		LookupEnv:   lookupEnvMap(map[string]string{"PROG_BOOL": "true"}),
+		ConfigFiles: getConfigFiles(dir),
+	}
+	sc.Register(newRemoteGroup(clitest.NewStringsFlagSetter("name"), nil))
+	sc.RegisterConfig("config", "", "")
+	return sc
+}
+
+func TestSubCommander_RegisterConfig_List(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"system": "int = 1\n[remote add]\nname = system",
+		"user":   "int = 2",
+	})
+	defer os.RemoveAll(dir)
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: newConfigSubCommander(dir),
+		Args:         strings.Fields("config list"),
+		OutString: strings.Join([]string{
+			"KEY              VALUE   SOURCE",
+			"bool             true    env PROG_BOOL",
+			"int              2       config " + filepath.Join(dir, "user") + ":1",
+			"string                   default",
+			"remote.add.name  system  config " + filepath.Join(dir, "system") + ":3",
+			"",
+		}, "\n"),
+	})
+}
+
+func TestSubCommander_RegisterConfig_SetAndGet(t *testing.T) {
+	dir := tempConfigDir(t, nil)
+	defer os.RemoveAll(dir)
+
+	sets := [][]string{
+		{"int", "3"},
+		{"r.add.name", " origin "},
+		{"remote.add.name", "upstream"},
+	}
+	for i, set := range sets {
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: newConfigSubCommander(dir),
+			Args:         append([]string{"config", "set"}, set...),
+		}, i)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "int = 3\n\n[remote add]\nname = upstream\n"; string(content) != want {
+		t.Fatalf("content =\n%s\nWANT\n%s", content, want)
+	}
+
+	sc := newConfigSubCommander(dir)
+	gfs := sc.GlobalFlags.(*clitest.SimpleFlagSetter)
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("config set int 4"),
+	})
+	if gfs.Int != 3 {
+		t.Errorf("Int = %v WANT %v", gfs.Int, 3)
+	}
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: newConfigSubCommander(dir),
+		Args:         strings.Fields("config get remote.add.name"),
+		OutString:    "upstream\n",
+	})
+}
+
+func TestSubCommander_RegisterConfig_Errors(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "string = value"})
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		args []string
+		err  error
+	}{
+		{strings.Fields("config get int"), ConfigKeyNotSetError("int")},
+		{strings.Fields("config get remote.add.other"), cli.UnknownConfigKeyError("remote.add.other")},
+		{strings.Fields("config set other value"), cli.UnknownConfigKeyError("other")},
+	}
+
+	for i, test := range tests {
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: newConfigSubCommander(dir),
+			Args:         test.args,
+			Err:          &ExecutingSubCommandError{test.err},
+		}, i)
+	}
+
+	_, _, err := executeContext(newConfigSubCommander(dir), nil, strings.Fields("config set int one"), nil)
+	if !IsExecutionError(err) || !strings.HasPrefix(err.Error(), `invalid value "one" for int: `) {
+		t.Errorf("err = %v", err)
+	}
+
+	_, _, err = executeContext(newConfigSubCommander(dir), nil, strings.Fields("config set int"), nil)
+	if _, ok := err.(*ParsingSubCommandError); !ok {
+		t.Errorf("err = %v", err)
+	}
+
+	sc := newConfigSubCommander(dir)
+	sc.ConfigFiles.User = ""
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("config get int"),
+		Err:          &ExecutingSubCommandError{ErrNoUserConfigFile},
+	})
+}
+
+func TestSubCommander_RegisterConfig_Init(t *testing.T) {
+	dir := tempConfigDir(t, nil)
+	defer os.RemoveAll(dir)
+
+	sc := newConfigSubCommander(dir)
+	sc.LookupEnv = lookupEnvMap(nil)
+	sc.ConfigFiles.User = filepath.Join(dir, "prog", "config")
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("config init"),
+		OutString:    sc.ConfigFiles.User + "\n",
+	})
+
+	content, err := ioutil.ReadFile(sc.ConfigFiles.User)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"# Configuration for prog.",
+		"",
+		"# bool_usage [$PROG_BOOL]",
+		"#bool = false",
+		"",
+		"# int_usage [$PROG_INT]",
+		"#int = 0",
+		"",
+		"# string_usage [$PROG_STRING]",
+		"#string =",
+		"",
+		"[remote add]",
+		"",
+		"# name_usage [$PROG_REMOTE_ADD_NAME]",
+		"#name = name_default",
+		"",
+	}, "\n")
+	if string(content) != want {
+		t.Fatalf("content =\n%s\nWANT\n%s", content, want)
+	}
+
+	_, _, err = executeContext(sc, nil, strings.Fields("config init"), nil)
+	if pe, ok := err.(*ExecutingSubCommandError).Err.(*os.PathError); !ok || pe.Err != os.ErrExist {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSubCommander_RegisterConfig_SetDoesNotSetSecrets(t *testing.T) {
+	dir := tempConfigDir(t, nil)
+	defer os.RemoveAll(dir)
+
+	password := ""
+	sc := newConfigSubCommander(dir)
+	sc.GlobalFlags = clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+		cli.SecretVar(f, &password, "password", "")
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"config", "set", "password", cli.SecretStdin},
+	})
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "user"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "password = -\n"; string(content) != want || len(password) > 0 {
+		t.Fatalf("content, password = %q, %q WANT %q", content, password, want)
+	}
+}
+
+func TestSubCommander_RegisterConfig_GetMasksSecrets(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "password = hunter2"})
+	defer os.RemoveAll(dir)
+
+	password := ""
+	sc := newConfigSubCommander(dir)
+	sc.GlobalFlags = clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+		cli.SecretVar(f, &password, "password", "")
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("config get password"),
+		OutString:    cli.SecretMask + "\n",
+	})
+}
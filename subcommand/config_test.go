@@ -0,0 +1,208 @@
+package subcommand
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestSubCommander_ExecuteContext_SetsFlagsFromConfigFiles(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"system":  "int1 = 1\nbool1\n[remote]\nstring2 = system\n[remote add]\nstring3 = system",
+		"user":    "string1 = user\n[remote add]\nstring3 = user\nint3 = 3",
+		"project": "[remote]\nint2 = 2\n[remote add]\nstring3 = project",
+	})
+	defer os.RemoveAll(dir)
+
+	for _, disallow := range []bool{false, true} {
+		gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+		rfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+		afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+
+		remote := &Group{NameValue: "remote", FlagSetter: rfs}
+		remote.Register(&SubCommandStruct{NameValue: "add", FlagSetter: afs})
+
+		sc := &SubCommander{
+			GlobalFlags:                       gfs,
+			DisallowGlobalFlagsWithSubCommand: disallow,
+			EnvPrefix:                         "prog",
+			LookupEnv:                         lookupEnvMap(map[string]string{"PROG_REMOTE_ADD_INT3": "30"}),
+			ConfigFiles:                       getConfigFiles(dir),
+		}
+		sc.Register(remote)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("-string1 arg remote add"),
+		}, disallow)
+
+		if !reflect.DeepEqual(gfs, &clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "arg", Bool: true}) {
+			t.Errorf("%v: global flags = %+v", disallow, gfs)
+		}
+		if !reflect.DeepEqual(rfs, &clitest.SimpleFlagSetter{Suffix: "2", Int: 2, String: "system"}) {
+			t.Errorf("%v: group flags = %+v", disallow, rfs)
+		}
+		if !reflect.DeepEqual(afs, &clitest.SimpleFlagSetter{Suffix: "3", Int: 30, String: "project"}) {
+			t.Errorf("%v: sub-command flags = %+v", disallow, afs)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_ConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		user   string
+		global bool
+		line   int
+		err    error
+	}{
+		{"\nint1 = 1\nint2 = 2", true, 3, cli.UnknownConfigKeyError("int2")},
+		{"[sub]\nint1 = 1", true, 2, cli.UnknownConfigKeyError("int1")},
+		{"[other-sub]\ntypo = 1", true, 2, cli.UnknownConfigKeyError("typo")},
+		{"[other]\nint1 = 1", true, 1, cli.UnknownConfigSectionError("other")},
+		{"[sub other]", true, 1, cli.UnknownConfigSectionError("sub other")},
+		{"int1", false, 1, nil},
+	}
+
+	for i, test := range tests {
+		dir := tempConfigDir(t, map[string]string{"user": test.user})
+		defer os.RemoveAll(dir)
+
+		sc := &SubCommander{
+			GlobalFlags: &clitest.SimpleFlagSetter{Suffix: "1"},
+			ConfigFiles: getConfigFiles(dir),
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue:  "sub",
+			FlagSetter: &clitest.SimpleFlagSetter{Suffix: "2"},
+		})
+		sc.Register(&SubCommandStruct{
+			NameValue:  "other-sub",
+			FlagSetter: &clitest.SimpleFlagSetter{Suffix: "3"},
+		})
+
+		_, outErr, err := executeContext(sc, nil, []string{"sub"}, nil)
+
+		var ce *cli.ConfigError
+		if pgae, ok := err.(*ParsingGlobalArgsError); ok && test.global {
+			ce, _ = pgae.Err.(*cli.ConfigError)
+		}
+		if psce, ok := err.(*ParsingSubCommandError); ok && !test.global {
+			ce, _ = psce.Err.(*cli.ConfigError)
+		}
+		if ce == nil || ce.Path != filepath.Join(dir, "user") || ce.Line != test.line {
+			t.Errorf("%v: err = %#v", i, err)
+			continue
+		}
+		if test.err != nil && ce.Err != test.err {
+			t.Errorf("%v: err = %v WANT %v", i, ce.Err, test.err)
+		}
+		if !strings.HasPrefix(outErr.String(), ce.Error()+"\n\n") {
+			t.Errorf("%v: outErr = %v", i, outErr)
+		}
+	}
+}
+
+func tempConfigDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "subcommand")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func getConfigFiles(dir string) cli.ConfigFiles {
+	return cli.ConfigFiles{
+		System:  filepath.Join(dir, "system"),
+		User:    filepath.Join(dir, "user"),
+		Project: filepath.Join(dir, "project"),
+	}
+}
+
+//newRemoteGroup returns the Group "remote", aliased "r", with the SubCommand
+//"add" whose flags and parameters are set by fs and ps.
+func newRemoteGroup(fs cli.FlagSetter, ps cli.ParameterSetter) *Group {
+	remote := &Group{NameValue: "remote", AliasesValue: []string{"r"}}
+	remote.Register(&SubCommandStruct{
+		NameValue:       "add",
+		FlagSetter:      fs,
+		ParameterSetter: ps,
+	})
+	return remote
+}
+
+func TestSubCommander_ExecuteContext_OptionsFlagPrintsProvenanceTable(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "[sub]\nstring2 = config"})
+	defer os.RemoveAll(dir)
+
+	for _, disallow := range []bool{false, true} {
+		sc := &SubCommander{
+			GlobalFlags:                       &clitest.SimpleFlagSetter{Suffix: "1"},
+			DisallowGlobalFlagsWithSubCommand: disallow,
+			EnvPrefix:                         "prog",
+			LookupEnv:                         lookupEnvMap(map[string]string{"PROG_INT1": "1"}),
+			ConfigFiles:                       getConfigFiles(dir),
+			OptionsFlag:                       "show-options",
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue:    "sub",
+			FlagSetter:   &clitest.SimpleFlagSetter{Suffix: "2"},
+			ExecuteValue: clitest.NewExecuteFunc("executed", "", nil),
+		})
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("-show-options sub -bool2"),
+			OutString: strings.Join([]string{
+				"OPTION         VALUE   SOURCE",
+				"-bool1         false   default",
+				"-int1          1       env PROG_INT1",
+				"-show-options  true    command line",
+				"-string1               default",
+				"-bool2         true    command line",
+				"-int2          0       default",
+				"-string2       config  config " + filepath.Join(dir, "user") + ":2",
+				"",
+			}, "\n"),
+		}, disallow)
+	}
+}
+
+func TestSubCommander_ExecuteContext_ExecuteReceivesProvenance(t *testing.T) {
+	var p *cli.Provenance
+	sc := &SubCommander{
+		GlobalFlags: &clitest.SimpleFlagSetter{Suffix: "1"},
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue:  "sub",
+		FlagSetter: &clitest.SimpleFlagSetter{Suffix: "2"},
+		ExecuteValue: func(ctx context.Context, _ io.Reader, _, _ io.Writer) error {
+			p = cli.ProvenanceFromContext(ctx)
+			return nil
+		},
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("sub -int1 1"),
+	})
+
+	if option := p.Lookup("int1"); option == nil || option.Source.Kind != cli.SourceCommandLine {
+		t.Fatalf("int1 option = %v", option)
+	}
+	if option := p.Lookup("int2"); option == nil || option.Source.Kind != cli.SourceDefault {
+		t.Fatalf("int2 option = %v", option)
+	}
+}
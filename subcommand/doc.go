@@ -7,6 +7,51 @@
 //The SubCommander type is a wrapper that handles executing a SubCommand and providing
 //help and error output in the case of errors not related to executing a SubCommand.
 //
+//SubCommands may be organized into trees by registering a Group as a SubCommand.
+//A Group's sub-commands are named by the argument following the Group's name,
+//for example "prog remote add <name>". Flags set by a Group are inherited by all
+//of its descendants in the same way that global flags are.
+//
+//A SubCommander may also be loaded from a declarative JSON spec with Load.
+//Each loaded SubCommand is bound by name to a Handler that receives the parsed
+//flag values and parameters.
+//
+//Setting SubCommander.GNUOptions parses arguments with GNU getopt_long semantics,
+//e.g. "-abc" and "--verbose", instead of those of the flag package.
+//Setting SubCommander.NegativeNumbers parses negative numbers, e.g. "-5", as
+//parameters instead of flags.
+//
+//Setting SubCommander.ResponseFiles replaces arguments of the form @path by the
+//arguments in the file at path. See cli.ExpandResponseFiles.
+//
+//Setting SubCommander.EnvPrefix allows flags that are not present in the arguments
+//to take their values from environment variables. Global flags are named
+//PREFIX_FLAG and sub-command flags PREFIX_SUB_COMMAND_PATH_FLAG.
+//Setting SubCommander.EnvOpts also prepends default arguments from PREFIX_OPTS
+//and PREFIX_SUB_COMMAND_PATH_OPTS, which the command line overrides.
+//
+//SubCommander.ConfigFiles provides flag values from layered configuration files.
+//The precedence of a flag's value is: command line, environment, project file,
+//user file, system file, and then the flag's default.
+//
+//Setting SubCommander.ProfileFlag defines a global flag that selects a named
+//profile, whose [profile NAME ...] sections take precedence over the other
+//sections of the configuration files. Help output lists the available profiles.
+//
+//Setting SubCommander.AliasSection lets users define aliases of sub-commands and
+//their arguments in a section of the configuration files, e.g. "co = checkout -b".
+//
+//RegisterConfig registers a Group of SubCommands that list the effective
+//configuration and get, set, and initialize keys in the user configuration file.
+//
+//The source of each flag's value is recorded in a cli.Provenance that SubCommands
+//receive through the Context passed to Execute. Setting SubCommander.OptionsFlag
+//defines a global flag that prints the Provenance as a table instead.
+//
+//SubCommands find their configuration, cache, data, and state directories with
+//cli.DirsFromContext. They follow the XDG Base Directory Specification and may be
+//replaced in tests by setting SubCommander.Dirs.
+//
 //The help and error output follow the general form loosely based on Go templates:
 //	{{.ErrorIfAParsingErrorNotAnExecutionError}}
 //
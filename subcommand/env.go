@@ -0,0 +1,85 @@
+package subcommand
+
+import (
+	"flag"
+
+	"github.com/gogolfing/cli"
+)
+
+//getGlobalFlagSetter returns a FlagSetter for sc.GlobalFlags, sc.OptionsFlag,
+//and sc.ProfileFlag.
+//If sc.EnvPrefix is set, then the flags are annotated with their environment
+//variable names and those names are recorded in envNames if it is not nil.
+func (sc *SubCommander) getGlobalFlagSetter(envNames map[string]string) cli.FlagSetter {
+	fs := flagSetters{sc.GlobalFlags}
+	if len(sc.OptionsFlag) > 0 {
+		fs = append(fs, optionsFlagSetter(sc.OptionsFlag))
+	}
+	if len(sc.ProfileFlag) > 0 {
+		fs = append(fs, profileFlagSetter(sc.ProfileFlag))
+	}
+	if len(sc.EnvPrefix) == 0 {
+		return fs
+	}
+	return &cli.EnvFlagSetter{
+		FlagSetter: fs,
+		Prefix:     sc.EnvPrefix,
+		Names:      envNames,
+	}
+}
+
+//getSubCommandFlagSetter returns a FlagSetter for the flags of the SubCommand
+//at the end of path in the same way as getGlobalFlagSetter.
+func (sc *SubCommander) getSubCommandFlagSetter(path []SubCommand, envNames map[string]string) cli.FlagSetter {
+	subCommand := path[len(path)-1]
+	if len(sc.EnvPrefix) == 0 {
+		return subCommand
+	}
+	return &cli.EnvFlagSetter{
+		FlagSetter: subCommand,
+		Prefix:     cli.EnvName(append([]string{sc.EnvPrefix}, getPathNames(path)...)...),
+		Names:      envNames,
+	}
+}
+
+//getPathFlagSetter returns a FlagSetter that sets the flags of every SubCommand
+//in a path of SubCommands from sc's root.
+func (sc *SubCommander) getPathFlagSetter(path []SubCommand) cli.FlagSetter {
+	fss := make(flagSetters, 0, len(path))
+	for i := range path {
+		fss = append(fss, sc.getSubCommandFlagSetter(path[:i+1], nil))
+	}
+	return fss
+}
+
+//prependEnvOpts returns args preceded by the default arguments of sc.EnvOpts for
+//the SubCommand at the end of path, or for the global flags if path is empty.
+//The flags of f that the default arguments set, when parsed with opts, are
+//recorded in s.provenance as set from the environment.
+func (sc *SubCommander) prependEnvOpts(path []SubCommand, f *flag.FlagSet, args []string, s *flagSources, opts cli.ParseOptions) ([]string, error) {
+	if !sc.EnvOpts || len(sc.EnvPrefix) == 0 {
+		return args, nil
+	}
+	parts := append(append([]string{sc.EnvPrefix}, getPathNames(path)...), cli.EnvOptsName)
+	name := cli.EnvName(parts...)
+	envArgs, err := cli.EnvArgs(name, sc.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	s.provenance.RecordEnvArgs(f, name, envArgs, args, opts)
+	return append(envArgs, args...), nil
+}
+
+//optionsFlagSetter sets the boolean flag of SubCommander.OptionsFlag.
+type optionsFlagSetter string
+
+func (ofs optionsFlagSetter) SetFlags(f *flag.FlagSet) {
+	f.Bool(string(ofs), false, cli.OptionsFlagUsage)
+}
+
+//isOptionsFlagSet returns whether or not the options flag named name in p has
+//the value true.
+func isOptionsFlagSet(p *cli.Provenance, name string) bool {
+	option := p.Lookup(name)
+	return len(name) > 0 && option != nil && option.Flag.Value.String() == "true"
+}
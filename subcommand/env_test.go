@@ -0,0 +1,231 @@
+package subcommand
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestSubCommander_GetPathFlagSetter_SetsFlagsOfEachSubCommand(t *testing.T) {
+	path := []SubCommand{
+		&Group{NameValue: "g", FlagSetter: clitest.NewStringsFlagSetter("a")},
+		&SubCommandStruct{NameValue: "s", FlagSetter: clitest.NewStringsFlagSetter("b")},
+	}
+
+	tests := []struct {
+		sc     *SubCommander
+		usageA string
+		usageB string
+	}{
+		{&SubCommander{}, "a_usage", "b_usage"},
+		{&SubCommander{EnvPrefix: "prog"}, "a_usage [$PROG_G_A]", "b_usage [$PROG_G_S_B]"},
+	}
+
+	for i, test := range tests {
+		f := cli.NewFlagSet("", test.sc.getPathFlagSetter(path))
+
+		if count := cli.CountFlags(f); count != 2 {
+			t.Fatalf("%v: CountFlags() = %v WANT %v", i, count, 2)
+		}
+		if usage := f.Lookup("a").Usage; usage != test.usageA {
+			t.Errorf("%v: a usage = %v WANT %v", i, usage, test.usageA)
+		}
+		if usage := f.Lookup("b").Usage; usage != test.usageB {
+			t.Errorf("%v: b usage = %v WANT %v", i, usage, test.usageB)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_SetsFlagsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"PROG_INT1":             "1",
+		"PROG_STRING1":          "global",
+		"PROG_REMOTE_INT2":      "2",
+		"PROG_REMOTE_ADD_INT3":  "3",
+		"PROG_REMOTE_ADD_BOOL3": "true",
+	}
+
+	for _, disallow := range []bool{false, true} {
+		gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+		rfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+		afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+
+		remote := &Group{NameValue: "remote", FlagSetter: rfs}
+		remote.Register(&SubCommandStruct{NameValue: "add", FlagSetter: afs})
+
+		sc := &SubCommander{
+			GlobalFlags:                       gfs,
+			DisallowGlobalFlagsWithSubCommand: disallow,
+			EnvPrefix:                         "prog",
+			LookupEnv:                         lookupEnvMap(env),
+		}
+		sc.Register(remote)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("-string1 arg remote add -int3 30"),
+		}, disallow)
+
+		if !reflect.DeepEqual(gfs, &clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "arg"}) {
+			t.Errorf("%v: global flags = %+v", disallow, gfs)
+		}
+		if !reflect.DeepEqual(rfs, &clitest.SimpleFlagSetter{Suffix: "2", Int: 2}) {
+			t.Errorf("%v: group flags = %+v", disallow, rfs)
+		}
+		if !reflect.DeepEqual(afs, &clitest.SimpleFlagSetter{Suffix: "3", Int: 30, Bool: true}) {
+			t.Errorf("%v: sub-command flags = %+v", disallow, afs)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_InvalidEnvValueIsParsingError(t *testing.T) {
+	sc := &SubCommander{
+		GlobalFlags: &clitest.SimpleFlagSetter{Suffix: "1"},
+		EnvPrefix:   "prog",
+		LookupEnv:   lookupEnvMap(map[string]string{"PROG_INT1": "one"}),
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue: "sub",
+		ExecuteValue: func(_ context.Context, _ io.Reader, _, _ io.Writer) error {
+			t.Fatal("should not execute")
+			return nil
+		},
+	})
+
+	_, _, err := executeContext(sc, nil, []string{"sub"}, nil)
+
+	pse, ok := err.(*ParsingSubCommandError)
+	if !ok {
+		t.Fatalf("err = %#v WANT *ParsingSubCommandError", err)
+	}
+	if eve, ok := pse.Err.(*cli.EnvValueError); !ok || eve.Name != "PROG_INT1" || eve.Value != "one" {
+		t.Fatalf("err = %#v WANT *cli.EnvValueError for PROG_INT1", pse.Err)
+	}
+}
+
+func TestSubCommander_ExecuteContext_HelpIncludesEnvNames(t *testing.T) {
+	sc := &SubCommander{
+		GlobalFlags: clitest.NewStringsFlagSetter("g"),
+		EnvPrefix:   "prog",
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue:  "sub",
+		FlagSetter: clitest.NewStringsFlagSetter("s"),
+	})
+
+	_, outErr, _ := executeContext(sc, nil, strings.Fields("sub -help"), nil)
+
+	for _, want := range []string{"g_usage [$PROG_G]", "s_usage [$PROG_SUB_S]"} {
+		if !strings.Contains(outErr.String(), want) {
+			t.Errorf("help output does not contain %q\n%v", want, outErr)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_PrependsEnvOpts(t *testing.T) {
+	for _, disallow := range []bool{false, true} {
+		gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+		rfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+		afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+
+		remote := &Group{NameValue: "remote", FlagSetter: rfs}
+		remote.Register(&SubCommandStruct{NameValue: "add", FlagSetter: afs})
+
+		sc := &SubCommander{
+			GlobalFlags:                       gfs,
+			DisallowGlobalFlagsWithSubCommand: disallow,
+			EnvPrefix:                         "prog",
+			EnvOpts:                           true,
+			LookupEnv: lookupEnvMap(map[string]string{
+				"PROG_OPTS":            "-int1 1 -string1 'global opts'",
+				"PROG_REMOTE_OPTS":     "-int2 2",
+				"PROG_REMOTE_ADD_OPTS": `-string3 "add opts" -bool3`,
+			}),
+		}
+		sc.Register(remote)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("-string1 arg remote -int2 20 add"),
+		}, disallow)
+
+		if !reflect.DeepEqual(gfs, &clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "arg"}) {
+			t.Errorf("%v: global flags = %+v", disallow, gfs)
+		}
+		if !reflect.DeepEqual(rfs, &clitest.SimpleFlagSetter{Suffix: "2", Int: 20}) {
+			t.Errorf("%v: group flags = %+v", disallow, rfs)
+		}
+		if !reflect.DeepEqual(afs, &clitest.SimpleFlagSetter{Suffix: "3", String: "add opts", Bool: true}) {
+			t.Errorf("%v: sub-command flags = %+v", disallow, afs)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_OptionsFlagRecordsEnvOptsAsEnv(t *testing.T) {
+	remote := &Group{NameValue: "remote", FlagSetter: &clitest.SimpleFlagSetter{Suffix: "2"}}
+	remote.Register(&SubCommandStruct{NameValue: "add", FlagSetter: &clitest.SimpleFlagSetter{Suffix: "3"}})
+
+	sc := &SubCommander{
+		GlobalFlags: &clitest.SimpleFlagSetter{Suffix: "1"},
+		EnvPrefix:   "prog",
+		EnvOpts:     true,
+		LookupEnv: lookupEnvMap(map[string]string{
+			"PROG_OPTS":            "-int1 1 -string1 opts",
+			"PROG_REMOTE_OPTS":     "-int2 2",
+			"PROG_REMOTE_ADD_OPTS": "-bool3",
+		}),
+		OptionsFlag: "options",
+	}
+	sc.Register(remote)
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("-options -string1 arg remote add -int3 3"),
+		OutString: strings.Join([]string{
+			"OPTION    VALUE  SOURCE",
+			"-bool1    false  default",
+			"-int1     1      env PROG_OPTS",
+			"-options  true   command line",
+			"-string1  arg    command line",
+			"-bool2    false  default",
+			"-int2     2      env PROG_REMOTE_OPTS",
+			"-string2         default",
+			"-bool3    true   env PROG_REMOTE_ADD_OPTS",
+			"-int3     3      command line",
+			"-string3         default",
+			"",
+		}, "\n"),
+	})
+}
+
+func TestSubCommander_ExecuteContext_InvalidEnvOptsIsParsingError(t *testing.T) {
+	sc := &SubCommander{
+		EnvPrefix: "prog",
+		EnvOpts:   true,
+		LookupEnv: lookupEnvMap(map[string]string{"PROG_SUB_OPTS": "'unterminated"}),
+	}
+	sc.Register(&SubCommandStruct{NameValue: "sub"})
+
+	_, _, err := executeContext(sc, nil, []string{"sub"}, nil)
+
+	want := &ParsingSubCommandError{&cli.EnvValueError{
+		Name:  "PROG_SUB_OPTS",
+		Value: "'unterminated",
+		Err:   cli.ErrUnterminatedQuote,
+	}}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("err = %#v WANT %#v", err, want)
+	}
+}
+
+func lookupEnvMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
@@ -16,6 +16,14 @@ func (e UnknownSubCommandError) Error() string {
 	return fmt.Sprintf("unknown %v %q", SubCommandName, string(e))
 }
 
+//UnknownShellError is an error denoting the provided shell is not one of Shells.
+type UnknownShellError string
+
+//Error provides the error implementation.
+func (e UnknownShellError) Error() string {
+	return fmt.Sprintf("unknown %v %q", ShellName, string(e))
+}
+
 //ParsingGlobalArgsError is an error wrapper denoting global argument parsing failed.
 type ParsingGlobalArgsError struct {
 	Err error
@@ -53,3 +61,47 @@ func IsExecutionError(err error) bool {
 	_, ok := err.(*ExecutingSubCommandError)
 	return ok
 }
+
+//ErrNoUserConfigFile is an error denoting that a SubCommand registered by
+//RegisterConfig needs the user configuration file but SubCommander.ConfigFiles.User
+//is empty.
+var ErrNoUserConfigFile = fmt.Errorf("no user configuration file")
+
+//ConfigKeyNotSetError is an error denoting the provided configuration key is not
+//set in the user configuration file.
+type ConfigKeyNotSetError string
+
+//Error provides the error implementation.
+func (e ConfigKeyNotSetError) Error() string {
+	return fmt.Sprintf("configuration key %q is not set", string(e))
+}
+
+//UnknownProfileError is an error denoting the selected profile has no sections
+//in the configuration files.
+type UnknownProfileError string
+
+//Error provides the error implementation.
+func (e UnknownProfileError) Error() string {
+	return fmt.Sprintf("unknown profile %q", string(e))
+}
+
+//ErrEmptyAlias is an error denoting a user alias expands to no arguments.
+var ErrEmptyAlias = fmt.Errorf("alias expands to no arguments")
+
+//RecursiveAliasError is an error denoting a user alias expands to itself,
+//directly or through other user aliases.
+type RecursiveAliasError string
+
+//Error provides the error implementation.
+func (e RecursiveAliasError) Error() string {
+	return fmt.Sprintf("recursive alias %q", string(e))
+}
+
+//AliasShadowsSubCommandError is an error denoting a user alias has the name or
+//alias of a registered sub-command.
+type AliasShadowsSubCommandError string
+
+//Error provides the error implementation.
+func (e AliasShadowsSubCommandError) Error() string {
+	return fmt.Sprintf("alias %q shadows a %v", string(e), SubCommandName)
+}
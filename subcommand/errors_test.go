@@ -20,6 +20,14 @@ func TestUnknownSubCommandError_Error(t *testing.T) {
 	}
 }
 
+func TestUnknownShellError_Error(t *testing.T) {
+	err := UnknownShellError("csh")
+
+	if result := err.Error(); result != `unknown shell "csh"` {
+		t.Fail()
+	}
+}
+
 func TestParsingGlobalArgsError_Error(t *testing.T) {
 	err := &ParsingGlobalArgsError{errors.New(t.Name())}
 	if err.Error() != t.Name() {
@@ -52,3 +60,35 @@ func TestIsExecutionError(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestConfigKeyNotSetError_Error(t *testing.T) {
+	err := ConfigKeyNotSetError("remote.add.name")
+
+	if result := err.Error(); result != `configuration key "remote.add.name" is not set` {
+		t.Fatal(result)
+	}
+}
+
+func TestUnknownProfileError_Error(t *testing.T) {
+	err := UnknownProfileError("dev")
+
+	if result := err.Error(); result != `unknown profile "dev"` {
+		t.Fatal(result)
+	}
+}
+
+func TestRecursiveAliasError_Error(t *testing.T) {
+	err := RecursiveAliasError("co")
+
+	if result := err.Error(); result != `recursive alias "co"` {
+		t.Fatal(result)
+	}
+}
+
+func TestAliasShadowsSubCommandError_Error(t *testing.T) {
+	err := AliasShadowsSubCommandError("list")
+
+	if result := err.Error(); result != `alias "list" shadows a `+SubCommandName {
+		t.Fatal(result)
+	}
+}
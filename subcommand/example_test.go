@@ -2,6 +2,7 @@ package subcommand
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -219,8 +220,9 @@ func Example_helpSubCommand() {
 
 	// Output:
 	// sub1 - This is a description.
+	//
 	// usage: ... sub1 [parameters...]
-
+	//
 	// parameters: <FILES...>
 	// <FILES...> are the files to process
 }
@@ -253,3 +255,86 @@ func Example_listSubCommand() {
 	//   list, subcommands    Prints available sub_commands
 	//   sub1                 Synopsis for sub1
 }
+
+func Example_group() {
+	var verbose bool
+	add := &SubCommandStruct{
+		NameValue:     "add",
+		SynopsisValue: "Adds a remote",
+		ExecuteValue: func(_ context.Context, _ io.Reader, out, _ io.Writer) error {
+			fmt.Fprintln(out, "adding remote with verbose", verbose)
+			return nil
+		},
+	}
+
+	remote := &Group{
+		NameValue:     "remote",
+		SynopsisValue: "Manages remotes",
+		FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+			f.BoolVar(&verbose, "verbose", false, "print verbose output")
+		}),
+	}
+	remote.Register(add)
+
+	sc := &SubCommander{
+		CommandName: "example_group",
+	}
+	sc.Register(remote)
+
+	//The -verbose flag is inherited by all of remote's sub-commands.
+	err := sc.ExecuteContext(
+		context.Background(),
+		strings.Fields("remote add -verbose"),
+		os.Stdin,
+		os.Stdout,
+		os.Stdout,
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// adding remote with verbose true
+}
+
+func Example_load() {
+	specJSON := `{
+  "name": "example_load",
+  "sub_commands": [
+    {
+      "name": "greet",
+      "synopsis": "Greets someone",
+      "flags": [{"name": "times", "type": "int", "default": "1"}],
+      "parameters": [{"name": "name"}]
+    }
+  ]
+}`
+
+	greet := func(_ context.Context, inv *Invocation, _ io.Reader, out, _ io.Writer) error {
+		for i := 0; i < inv.Flags["times"].(int); i++ {
+			fmt.Fprintln(out, "hello", inv.Parameters[0])
+		}
+		return nil
+	}
+
+	sc, err := Load(strings.NewReader(specJSON), map[string]Handler{"greet": greet})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = sc.ExecuteContext(
+		context.Background(),
+		strings.Fields("greet -times 2 world"),
+		os.Stdin,
+		os.Stdout,
+		os.Stdout,
+	)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// hello world
+	// hello world
+}
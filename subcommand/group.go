@@ -0,0 +1,107 @@
+package subcommand
+
+import (
+	"context"
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//Group is a SubCommand that has SubCommands of its own.
+//Registering a Group with a SubCommander (or another Group) allows executing
+//trees of sub-commands such as:
+//	prog remote add <name>
+//
+//Flags set by a Group's FlagSetter are inherited by all of the Group's descendants
+//in the same way that SubCommander.GlobalFlags are inherited by all SubCommands.
+//That is, they may be present anywhere after the Group's name in the arguments.
+//
+//A Group is never executed itself. A SubCommander always descends into the Group
+//and executes the SubCommand named by the next argument.
+type Group struct {
+	//NameValue is returned from SubCommand's Name() method.
+	NameValue string
+
+	//AliasesValue is returned from SubCommand's Aliases() method.
+	AliasesValue []string
+
+	//SynopsisValue is returned from SubCommand's Synopsis() method.
+	SynopsisValue string
+
+	//DescriptionValue is returned from SubCommand's Description() method.
+	DescriptionValue string
+
+	//FlagSetter is used as the Group's implementation for SetFlags if not nil.
+	//These flags are inherited by all descendants of the Group.
+	cli.FlagSetter
+
+	registry
+}
+
+//Register registers subCommand as a child of g to be possibly executed later
+//via its Name() or Aliases().
+//This will overwrite any previously registered SubCommands with the same Name()s
+//or Aliases().
+func (g *Group) Register(subCommand SubCommand) {
+	g.register(subCommand)
+}
+
+//Name returns g.NameValue.
+func (g *Group) Name() string {
+	return g.NameValue
+}
+
+//Aliases returns g.AliasesValue.
+func (g *Group) Aliases() []string {
+	return g.AliasesValue
+}
+
+//Synopsis returns g.SynopsisValue.
+func (g *Group) Synopsis() string {
+	return g.SynopsisValue
+}
+
+//Description returns g.DescriptionValue.
+func (g *Group) Description() string {
+	return g.DescriptionValue
+}
+
+//SetFlags delegates to g.FlagSetter if the field is not nil.
+func (g *Group) SetFlags(f *flag.FlagSet) {
+	if g.FlagSetter != nil {
+		g.FlagSetter.SetFlags(f)
+	}
+}
+
+//ParameterUsage returns nil and the empty string.
+//The sub-commands of g are listed in help output instead.
+func (g *Group) ParameterUsage() ([]*cli.Parameter, string) {
+	return nil, ""
+}
+
+//SetParameters returns nil. It is never called by a SubCommander.
+func (g *Group) SetParameters(_ []string) error {
+	return nil
+}
+
+//Execute returns nil. It is never called by a SubCommander.
+func (g *Group) Execute(_ context.Context, _ io.Reader, _, _ io.Writer) error {
+	return nil
+}
+
+//getPathNames returns the Name()s of each SubCommand in path.
+func getPathNames(path []SubCommand) []string {
+	names := make([]string, 0, len(path))
+	for _, subCommand := range path {
+		names = append(names, subCommand.Name())
+	}
+	return names
+}
+
+//getPageName returns the name of a documentation page for the SubCommand at the
+//end of path, e.g. "prog-remote-add", where name is the command name.
+func getPageName(name string, path []SubCommand) string {
+	return strings.Join(append([]string{name}, getPathNames(path)...), "-")
+}
@@ -0,0 +1,257 @@
+package subcommand
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestGroup_Register_RegistersSubCommandsNameAndAliases(t *testing.T) {
+	g := &Group{}
+
+	subCommand := &SubCommandStruct{
+		NameValue:    "name",
+		AliasesValue: []string{"a"},
+	}
+	g.Register(subCommand)
+
+	if g.names["name"] != subCommand {
+		t.Fatalf("name should be registered with subCommand")
+	}
+	if g.aliases["a"] != subCommand {
+		t.Fatalf("a should be registered with subCommand")
+	}
+}
+
+func TestSubCommander_ExecuteContext_GroupExecutesNestedSubCommandWithInheritedFlags(t *testing.T) {
+	gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+	rfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+	afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+
+	var params []string
+	add := &SubCommandStruct{
+		NameValue:  "add",
+		FlagSetter: afs,
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			SetParametersValue: func(p []string) error {
+				params = p
+				return nil
+			},
+		},
+		ExecuteValue: clitest.NewExecuteFunc("add", "", nil),
+	}
+	remote := &Group{
+		NameValue:    "remote",
+		AliasesValue: []string{"r"},
+		FlagSetter:   rfs,
+	}
+	remote.Register(add)
+
+	sct := &SubCommanderTest{
+		SubCommander: &SubCommander{
+			GlobalFlags: gfs,
+		},
+		SubCommands: []SubCommand{remote},
+		Args:        strings.Fields("-int1 1 r -string2 two add name -bool1 -int2 2 -string3 three"),
+		OutString:   "add",
+	}
+
+	testSubCommanderTest(t, sct)
+
+	if !reflect.DeepEqual(params, []string{"name"}) {
+		t.Errorf("params = %v WANT %v", params, []string{"name"})
+	}
+	if !reflect.DeepEqual(gfs, &clitest.SimpleFlagSetter{Suffix: "1", Int: 1, Bool: true}) {
+		t.Error("global flags were not set correctly")
+	}
+	if !reflect.DeepEqual(rfs, &clitest.SimpleFlagSetter{Suffix: "2", Int: 2, String: "two"}) {
+		t.Error("group flags were not set correctly")
+	}
+	if !reflect.DeepEqual(afs, &clitest.SimpleFlagSetter{Suffix: "3", String: "three"}) {
+		t.Error("sub-command flags were not set correctly")
+	}
+}
+
+func TestSubCommander_ExecuteContext_GroupErrors(t *testing.T) {
+	rfs := clitest.NewStringsFlagSetter("r1")
+	groupUsage := Usage + " ... remote [sub_command_options...] <sub_command> [[sub_command_options | parameters]...]" + "\n\n" +
+		SubCommandOptionsName + ":\n" + clitest.GetFlagSetterDefaults(rfs) + "\n\n" +
+		SubCommandsName + ":\n" + "  add             add synopsis" + "\n"
+
+	tests := []struct {
+		args []string
+		err  error
+	}{
+		{strings.Fields("remote"), ErrUnsuppliedSubCommand},
+		{strings.Fields("remote -r1 value"), ErrUnsuppliedSubCommand},
+		{strings.Fields("remote foo"), UnknownSubCommandError("foo")},
+		{strings.Fields("remote -foo"), errors.New("flag provided but not defined: -foo")},
+	}
+
+	for i, test := range tests {
+		remote := &Group{
+			NameValue:  "remote",
+			FlagSetter: rfs,
+		}
+		remote.Register(&SubCommandStruct{
+			NameValue:     "add",
+			SynopsisValue: "add synopsis",
+		})
+
+		sct := &SubCommanderTest{
+			SubCommands:  []SubCommand{remote},
+			Args:         test.args,
+			OutErrString: test.err.Error() + "\n\n" + groupUsage,
+			Err:          &ParsingSubCommandError{test.err},
+		}
+
+		testSubCommanderTest(t, sct, i)
+	}
+}
+
+func TestSubCommander_ExecuteContext_GroupNestedSubCommandErrorPrintsInheritedOptions(t *testing.T) {
+	rfs := clitest.NewStringsFlagSetter("r1")
+	afs := clitest.NewStringsFlagSetter("a1")
+	err := errors.New("flag provided but not defined: -foo")
+
+	remote := &Group{
+		NameValue:  "remote",
+		FlagSetter: rfs,
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:  "add",
+		FlagSetter: afs,
+	})
+
+	sct := &SubCommanderTest{
+		SubCommands: []SubCommand{remote},
+		Args:        strings.Fields("remote add -foo"),
+		OutErrString: err.Error() + "\n\n" + Usage + " ... remote add [sub_command_options...]" + "\n\n" +
+			SubCommandOptionsName + ":\n" + clitest.GetFlagSetterDefaults(clitest.NewStringsFlagSetter("a1", "r1")) + "\n",
+		Err: &ParsingSubCommandError{err},
+	}
+
+	testSubCommanderTest(t, sct)
+}
+
+func TestSubCommander_ExecuteContext_GroupsCanBeNestedInGroups(t *testing.T) {
+	called := false
+	leaf := &SubCommandStruct{
+		NameValue: "c",
+		ExecuteValue: func(_ context.Context, _ io.Reader, _, _ io.Writer) error {
+			called = true
+			return nil
+		},
+	}
+	b := &Group{NameValue: "b"}
+	b.Register(leaf)
+	a := &Group{NameValue: "a"}
+	a.Register(b)
+
+	sct := &SubCommanderTest{
+		SubCommands: []SubCommand{a},
+		Args:        strings.Fields("a b c"),
+	}
+
+	testSubCommanderTest(t, sct)
+
+	if !called {
+		t.Fatal("nested sub-command was not executed")
+	}
+}
+
+func TestSubCommander_ExecuteContext_RegisteredHelpAcceptsFullPath(t *testing.T) {
+	remote := &Group{
+		NameValue:        "remote",
+		DescriptionValue: "remote_description",
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:        "add",
+		SynopsisValue:    "add synopsis",
+		DescriptionValue: "add_description",
+	})
+
+	tests := []struct {
+		args      []string
+		outString string
+	}{
+		{
+			strings.Fields("help remote add"),
+			"add - add_description" + "\n\n" + Usage + " ... remote add" + "\n",
+		},
+		{
+			strings.Fields("help remote"),
+			"remote - remote_description" + "\n\n" + Usage + " ... remote <sub_command> [[sub_command_options | parameters]...]" + "\n\n" +
+				SubCommandsName + ":\n" + "  add             add synopsis" + "\n",
+		},
+	}
+
+	for i, test := range tests {
+		sc := &SubCommander{}
+		sc.Register(remote)
+
+		sct := &SubCommanderTest{
+			SubCommander: sc,
+			RegisterHelp: true,
+			Args:         test.args,
+			OutString:    test.outString,
+		}
+
+		testSubCommanderTest(t, sct, i)
+	}
+}
+
+func TestSubCommander_ExecuteContext_RegisteredHelpWithUnknownNestedSubCommand(t *testing.T) {
+	remote := &Group{NameValue: "remote"}
+	remote.Register(&SubCommandStruct{
+		NameValue:     "add",
+		SynopsisValue: "add synopsis",
+	})
+	err := UnknownSubCommandError("foo")
+
+	sct := &SubCommanderTest{
+		SubCommands:  []SubCommand{remote},
+		RegisterHelp: true,
+		Args:         strings.Fields("help remote foo"),
+		OutErrString: err.Error() + "\n\n" + Usage + " ... remote <sub_command> [[sub_command_options | parameters]...]" + "\n\n" +
+			SubCommandsName + ":\n" + "  add             add synopsis" + "\n",
+		Err: &ExecutingSubCommandError{err},
+	}
+
+	testSubCommanderTest(t, sct)
+}
+
+func TestSubCommander_ExecuteContext_RegisteredHelpErrorsWithPathThroughNonGroup(t *testing.T) {
+	err := cli.ErrTooManyParameters
+
+	sct := &SubCommanderTest{
+		SubCommands: []SubCommand{
+			&SubCommandStruct{NameValue: "sub"},
+		},
+		RegisterHelp: true,
+		Args:         strings.Fields("help sub other"),
+		Err:          &ParsingSubCommandError{err},
+	}
+	sct.OutErrString = err.Error() + "\n\n" + Usage + " ... help [parameters...]" + "\n\n" +
+		ParametersName + ": <SUB_COMMAND>" + "\n" +
+		"<SUB_COMMAND> is the " + SubCommandName + " to provide help for" + "\n"
+
+	testSubCommanderTest(t, sct)
+}
+
+func TestGetPathNames(t *testing.T) {
+	names := getPathNames([]SubCommand{
+		&Group{NameValue: "a"},
+		&SubCommandStruct{NameValue: "b"},
+	})
+
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Fatal(names)
+	}
+}
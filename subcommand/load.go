@@ -0,0 +1,239 @@
+package subcommand
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/spec"
+)
+
+//Handler is the Go function bound to a SubCommand loaded from a spec.
+type Handler func(ctx context.Context, inv *Invocation, in io.Reader, out, outErr io.Writer) error
+
+//Invocation holds the parsed arguments passed to a Handler.
+type Invocation struct {
+	//Path is the names of the SubCommands from the root to the executing SubCommand,
+	//e.g. ["remote", "add"].
+	Path []string
+
+	//Flags are the values of the global flags, the flags of any Groups in Path,
+	//and the flags of the executing SubCommand by their names.
+	//Values have the Go type of the flag's spec type, e.g. int for "int" and
+	//time.Duration for "duration".
+	Flags map[string]interface{}
+
+	//Parameters are the parameters supplied to the executing SubCommand.
+	Parameters []string
+}
+
+//Load reads a JSON spec from in with spec.Read and returns the result of
+//NewSubCommanderFromSpec.
+func Load(in io.Reader, handlers map[string]Handler) (*SubCommander, error) {
+	s, err := spec.Read(in)
+	if err != nil {
+		return nil, err
+	}
+	return NewSubCommanderFromSpec(s, handlers)
+}
+
+//NewSubCommanderFromSpec returns a SubCommander with the global flags and SubCommands
+//described by s.
+//Commands in s with SubCommands are registered as Groups.
+//
+//All other commands are bound to the Handler in handlers named by their Handler
+//field or, if that is empty, the names of the command and its ancestors joined
+//by " ", e.g. "remote add".
+//
+//s is validated with spec.Validate. That error, or a missing Handler, is returned
+//as a *spec.Error with the location of the problem.
+func NewSubCommanderFromSpec(s *spec.Command, handlers map[string]Handler) (*SubCommander, error) {
+	if err := spec.Validate(s); err != nil {
+		return nil, err
+	}
+
+	globals, err := newSpecFlagSetter("", s.GlobalFlags, "global_flags")
+	if err != nil {
+		return nil, err
+	}
+	sc := &SubCommander{
+		CommandName:                       s.Name,
+		GlobalFlags:                       globals,
+		DisallowGlobalFlagsWithSubCommand: s.DisallowGlobalFlagsWithSubCommand,
+	}
+
+	subCommands, err := newSpecSubCommands("", s.SubCommands, []*specFlagSetter{globals}, nil, handlers)
+	if err != nil {
+		return nil, err
+	}
+	for _, subCommand := range subCommands {
+		sc.Register(subCommand)
+	}
+
+	return sc, nil
+}
+
+func newSpecSubCommands(location string, specs []*spec.Command, scope []*specFlagSetter, names []string, handlers map[string]Handler) ([]SubCommand, error) {
+	result := make([]SubCommand, 0, len(specs))
+
+	for i, s := range specs {
+		subLocation := spec.JoinLocation(location, "sub_commands", i)
+		subNames := append(append([]string{}, names...), s.Name)
+		flagSetter, err := newSpecFlagSetter(subLocation, s.Flags, "flags")
+		if err != nil {
+			return nil, err
+		}
+		subScope := append(append([]*specFlagSetter{}, scope...), flagSetter)
+
+		if len(s.SubCommands) > 0 {
+			group := &Group{
+				NameValue:        s.Name,
+				AliasesValue:     s.Aliases,
+				SynopsisValue:    s.Synopsis,
+				DescriptionValue: s.Description,
+				FlagSetter:       flagSetter,
+			}
+			children, err := newSpecSubCommands(subLocation, s.SubCommands, subScope, subNames, handlers)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				group.Register(child)
+			}
+			result = append(result, group)
+			continue
+		}
+
+		handlerName := s.Handler
+		if len(handlerName) == 0 {
+			handlerName = strings.Join(subNames, " ")
+		}
+		handler, ok := handlers[handlerName]
+		if !ok || handler == nil {
+			return nil, &spec.Error{
+				Location: spec.JoinLocation(subLocation, "handler", -1),
+				Err:      fmt.Errorf("unknown handler %q", handlerName),
+			}
+		}
+
+		result = append(result, &specSubCommand{
+			SubCommandStruct: &SubCommandStruct{
+				NameValue:        s.Name,
+				AliasesValue:     s.Aliases,
+				SynopsisValue:    s.Synopsis,
+				DescriptionValue: s.Description,
+				FlagSetter:       flagSetter,
+			},
+			spec:    s,
+			path:    subNames,
+			scope:   subScope,
+			handler: handler,
+		})
+	}
+
+	return result, nil
+}
+
+//specSubCommand is a SubCommand loaded from a spec.
+type specSubCommand struct {
+	*SubCommandStruct
+
+	spec    *spec.Command
+	path    []string
+	scope   []*specFlagSetter
+	handler Handler
+
+	params []string
+}
+
+func (s *specSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
+	return s.parameters(), s.spec.ParameterUsage
+}
+
+func (s *specSubCommand) parameters() []*cli.Parameter {
+	params := make([]*cli.Parameter, 0, len(s.spec.Parameters))
+	for _, param := range s.spec.Parameters {
+		params = append(params, &cli.Parameter{
+			Name:     param.Name,
+			Optional: param.Optional,
+			Many:     param.Many,
+		})
+	}
+	return params
+}
+
+//SetParameters ensures that all required parameters are present and that there
+//are not more params than the spec's parameters allow.
+func (s *specSubCommand) SetParameters(params []string) error {
+	specParams := s.parameters()
+	for i, param := range specParams {
+		if !param.Optional && i >= len(params) {
+			return &cli.RequiredParameterNotSetError{
+				Name:      param.Name,
+				Many:      param.Many,
+				Formatted: FormatParameter(param),
+			}
+		}
+	}
+	n := len(specParams)
+	if len(params) > n && (n == 0 || !specParams[n-1].Many) {
+		return cli.ErrTooManyParameters
+	}
+
+	s.params = params
+	return nil
+}
+
+func (s *specSubCommand) Execute(ctx context.Context, in io.Reader, out, outErr io.Writer) error {
+	inv := &Invocation{
+		Path:       s.path,
+		Flags:      map[string]interface{}{},
+		Parameters: s.params,
+	}
+	for _, flagSetter := range s.scope {
+		flagSetter.addValues(inv.Flags)
+	}
+
+	return s.handler(ctx, inv, in, out, outErr)
+}
+
+//specFlagSetter is a FlagSetter that defines flags from a spec.
+//The values of its flags are stored in vars, in the same order as flags.
+type specFlagSetter struct {
+	flags []*spec.Flag
+	vars  []interface{}
+}
+
+//newSpecFlagSetter returns a specFlagSetter for flags, which are at field of
+//location in the spec. An invalid flag is returned as a *spec.Error.
+func newSpecFlagSetter(location string, flags []*spec.Flag, field string) (*specFlagSetter, error) {
+	s := &specFlagSetter{flags: flags, vars: make([]interface{}, 0, len(flags))}
+	for i, fl := range flags {
+		p, err := spec.NewFlagVar(fl)
+		if err != nil {
+			return nil, &spec.Error{Location: spec.JoinLocation(location, field, i), Err: err}
+		}
+		s.vars = append(s.vars, p)
+	}
+	return s, nil
+}
+
+//SetFlags defines the flags in f and sets their values to their defaults.
+func (s *specFlagSetter) SetFlags(f *flag.FlagSet) {
+	for i, fl := range s.flags {
+		if err := spec.DefineFlagVar(f, fl, s.vars[i]); err != nil {
+			//newSpecFlagSetter has already created vars from the flags.
+			panic(err)
+		}
+	}
+}
+
+func (s *specFlagSetter) addValues(values map[string]interface{}) {
+	for i, fl := range s.flags {
+		values[fl.Name] = reflect.ValueOf(s.vars[i]).Elem().Interface()
+	}
+}
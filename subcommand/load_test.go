@@ -0,0 +1,179 @@
+package subcommand
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/spec"
+)
+
+const testLoadSpec = `{
+  "name": "prog",
+  "global_flags": [
+    {"name": "verbose", "type": "bool"}
+  ],
+  "sub_commands": [
+    {
+      "name": "remote",
+      "synopsis": "Manages remotes",
+      "flags": [
+        {"name": "timeout", "type": "duration", "default": "1s"}
+      ],
+      "sub_commands": [
+        {
+          "name": "add",
+          "aliases": ["a"],
+          "synopsis": "Adds a remote",
+          "flags": [
+            {"name": "count", "type": "int", "default": "2", "usage": "number of times"}
+          ],
+          "parameters": [
+            {"name": "name"},
+            {"name": "url", "optional": true}
+          ]
+        }
+      ]
+    },
+    {
+      "name": "version",
+      "handler": "print version"
+    }
+  ]
+}`
+
+func TestLoad_ExecutesHandlersWithFlagsAndParameters(t *testing.T) {
+	var inv *Invocation
+	handler := func(_ context.Context, i *Invocation, _ io.Reader, out, _ io.Writer) error {
+		inv = i
+		fmt.Fprint(out, strings.Join(i.Path, " "))
+		return nil
+	}
+
+	sc, err := Load(strings.NewReader(testLoadSpec), map[string]Handler{
+		"remote add":    handler,
+		"print version": handler,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sct := &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("remote -verbose a -count 3 origin"),
+		OutString:    "remote add",
+	}
+	testSubCommanderTest(t, sct)
+
+	want := &Invocation{
+		Path: []string{"remote", "add"},
+		Flags: map[string]interface{}{
+			"verbose": true,
+			"timeout": time.Second,
+			"count":   3,
+		},
+		Parameters: []string{"origin"},
+	}
+	if !reflect.DeepEqual(inv, want) {
+		t.Fatalf("Invocation = %v WANT %v", inv, want)
+	}
+
+	sct = &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"version"},
+		OutString:    "version",
+	}
+	testSubCommanderTest(t, sct)
+}
+
+func TestLoad_ParameterErrors(t *testing.T) {
+	handlers := map[string]Handler{
+		"remote add":    nopHandler,
+		"print version": nopHandler,
+	}
+
+	tests := []struct {
+		args []string
+		err  error
+	}{
+		{
+			strings.Fields("remote add"),
+			&cli.RequiredParameterNotSetError{Name: "name", Formatted: "<NAME>"},
+		},
+		{
+			strings.Fields("remote add origin url extra"),
+			cli.ErrTooManyParameters,
+		},
+		{
+			strings.Fields("version extra"),
+			cli.ErrTooManyParameters,
+		},
+	}
+
+	for i, test := range tests {
+		sc, err := Load(strings.NewReader(testLoadSpec), handlers)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err = executeContext(sc, nil, test.args, nil)
+
+		if !reflect.DeepEqual(err, &ParsingSubCommandError{test.err}) {
+			t.Errorf("%v: error = %v WANT %v", i, err, test.err)
+		}
+	}
+}
+
+func TestLoad_ReturnsErrorForUnknownHandler(t *testing.T) {
+	_, err := Load(strings.NewReader(testLoadSpec), map[string]Handler{
+		"remote add": nopHandler,
+	})
+
+	want := &spec.Error{
+		Location: "sub_commands[1].handler",
+		Err:      fmt.Errorf("unknown handler %q", "print version"),
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("Load() error = %v WANT %v", err, want)
+	}
+}
+
+func TestLoad_ReturnsSpecErrors(t *testing.T) {
+	_, err := Load(strings.NewReader(`{"sub_commands": [{"name": "a", "flags": [{"name": "f", "type": "bytes"}]}]}`), nil)
+
+	specErr, ok := err.(*spec.Error)
+	if !ok || specErr.Location != "sub_commands[0].flags[0].type" {
+		t.Fatalf("Load() error = %v", err)
+	}
+}
+
+func nopHandler(_ context.Context, _ *Invocation, _ io.Reader, _, _ io.Writer) error {
+	return nil
+}
+
+func TestSpecFlagSetter_StoresValuesItself(t *testing.T) {
+	s, err := newSpecFlagSetter("", []*spec.Flag{{Name: "count", Type: spec.TypeInt, Default: "2"}}, "global_flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := cli.NewFlagSet("", s)
+	if err := f.Parse([]string{"-count", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]interface{}{}
+	s.addValues(values)
+	if !reflect.DeepEqual(values, map[string]interface{}{"count": 5}) {
+		t.Fatalf("values = %v", values)
+	}
+
+	_, err = newSpecFlagSetter("sub_commands[1]", []*spec.Flag{{Name: "n", Type: spec.TypeInt}, {Name: "count", Type: spec.TypeInt, Default: "two"}}, "flags")
+	if specErr, ok := err.(*spec.Error); !ok || specErr.Location != "sub_commands[1].flags[1]" {
+		t.Fatalf("newSpecFlagSetter() error = %v", err)
+	}
+}
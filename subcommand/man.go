@@ -0,0 +1,102 @@
+package subcommand
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/man"
+)
+
+//ManPages returns man pages for sc and all of its SubCommands in the manual section.
+//
+//The first page is for sc itself. It has SYNOPSIS, OPTIONS (the global options),
+//SUB-COMMANDS, and SEE ALSO sections.
+//Each SubCommand, including those in Groups, has a page named by joining the
+//names of the command and SubCommands with "-", e.g. "prog-remote-add".
+//These pages have NAME, SYNOPSIS, DESCRIPTION, OPTIONS, GLOBAL OPTIONS, PARAMETERS
+//(or SUB-COMMANDS for Groups), and SEE ALSO sections if they are applicable.
+//The SYNOPSIS of each page is the same as the usage line in help output.
+func (sc *SubCommander) ManPages(section string) []*man.Page {
+	name := filepath.Base(sc.CommandName)
+
+	p := &man.Page{
+		Name:    name,
+		Section: section,
+	}
+	p.AddSection(man.Name, name)
+	p.AddSection(man.Synopsis, sc.getCommandUsage(name))
+	p.AddSection(man.Options, "", man.NewFlagItems(sc.globalFlagSet())...)
+	p.AddSection(man.SubCommands, "", getSubCommandManItems(&sc.registry)...)
+	p.AddSection(man.SeeAlso, getSeeAlsoManText(section, getChildManPageNames(name, &sc.registry)...))
+
+	return sc.appendSubCommandManPages([]*man.Page{p}, name, section, nil, &sc.registry)
+}
+
+func (sc *SubCommander) appendSubCommandManPages(pages []*man.Page, name, section string, parent []SubCommand, r *registry) []*man.Page {
+	parentName := getPageName(name, parent)
+
+	for _, subCommandName := range r.sortedSubCommandNames() {
+		subCommand := r.names[subCommandName]
+		path := append(append([]SubCommand{}, parent...), subCommand)
+		pageName := getPageName(name, path)
+
+		p := &man.Page{
+			Name:    pageName,
+			Section: section,
+		}
+		p.AddSection(man.Name, man.NameText(pageName, subCommand.Synopsis()))
+		p.AddSection(man.Synopsis, name+" "+sc.getSubCommandUsage(path, true))
+		p.AddSection(man.Description, subCommand.Description())
+		p.AddSection(man.Options, "", man.NewFlagItems(cli.NewFlagSet(subCommand.Name(), sc.getPathFlagSetter(path)))...)
+		if !sc.DisallowGlobalFlagsWithSubCommand {
+			p.AddSection(man.GlobalOptions, "", man.NewFlagItems(sc.globalFlagSet())...)
+		}
+
+		seeAlso := []string{parentName}
+		group, isGroup := subCommand.(*Group)
+		if isGroup {
+			p.AddSection(man.SubCommands, "", getSubCommandManItems(&group.registry)...)
+			seeAlso = append(seeAlso, getChildManPageNames(pageName, &group.registry)...)
+		} else {
+			params, usage := subCommand.ParameterUsage()
+			p.AddSection(man.Parameters, usage, man.NewParameterItems(params, FormatParameter)...)
+		}
+		p.AddSection(man.SeeAlso, getSeeAlsoManText(section, seeAlso...))
+
+		pages = append(pages, p)
+		if isGroup {
+			pages = sc.appendSubCommandManPages(pages, name, section, path, &group.registry)
+		}
+	}
+
+	return pages
+}
+
+func getSubCommandManItems(r *registry) []*man.Item {
+	items := []*man.Item{}
+	for _, name := range r.sortedSubCommandNames() {
+		subCommand := r.names[name]
+		items = append(items, &man.Item{
+			Tag:  getSortedJoinedSubCommandNameAliases(subCommand),
+			Text: subCommand.Synopsis(),
+		})
+	}
+	return items
+}
+
+func getChildManPageNames(pageName string, r *registry) []string {
+	names := []string{}
+	for _, name := range r.sortedSubCommandNames() {
+		names = append(names, pageName+"-"+name)
+	}
+	return names
+}
+
+func getSeeAlsoManText(section string, pageNames ...string) string {
+	references := make([]string, 0, len(pageNames))
+	for _, pageName := range pageNames {
+		references = append(references, pageName+"("+section+")")
+	}
+	return strings.Join(references, ", ")
+}
@@ -0,0 +1,113 @@
+package subcommand
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+	"github.com/gogolfing/cli/man"
+)
+
+func TestSubCommander_ManPages(t *testing.T) {
+	remote := &Group{
+		NameValue:        "remote",
+		AliasesValue:     []string{"r"},
+		SynopsisValue:    "Manages remotes",
+		DescriptionValue: "Manages the set of remotes.",
+		FlagSetter:       clitest.NewStringsFlagSetter("r1"),
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:     "add",
+		SynopsisValue: "Adds a remote",
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			ParameterUsageValue: func() ([]*cli.Parameter, string) {
+				return []*cli.Parameter{{Name: "name"}}, ""
+			},
+		},
+	})
+
+	sc := &SubCommander{
+		CommandName: "/usr/bin/prog",
+		GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+	}
+	sc.Register(remote)
+	sc.RegisterCompletion("completion", "", "")
+
+	pages := sc.ManPages("1")
+
+	names := []string{}
+	for _, p := range pages {
+		names = append(names, p.FileName())
+	}
+	wantNames := []string{"prog.1", "prog-completion.1", "prog-remote.1", "prog-remote-add.1"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("page names = %v WANT %v", names, wantNames)
+	}
+
+	tests := []struct {
+		page     *man.Page
+		contains []string
+	}{
+		{
+			pages[0],
+			[]string{
+				".SH \"SYNOPSIS\"\nprog [global_options...] <sub_command> [[global_options | sub_command_options | parameters]...]\n",
+				".SH \"OPTIONS\"\n.TP\n\\fB\\-g1\\fR \\fIstring\\fR\n",
+				".TP\n\\fBremote, r\\fR\nManages remotes\n",
+				".SH \"SEE ALSO\"\nprog\\-completion(1), prog\\-remote(1)\n",
+			},
+		},
+		{
+			pages[2],
+			[]string{
+				".SH \"NAME\"\nprog\\-remote \\- Manages remotes\n",
+				".SH \"SYNOPSIS\"\nprog remote [[global_options | sub_command_options]...] <sub_command> [[global_options | sub_command_options | parameters]...]\n",
+				".SH \"DESCRIPTION\"\nManages the set of remotes.\n",
+				".SH \"OPTIONS\"\n.TP\n\\fB\\-r1\\fR \\fIstring\\fR\n",
+				".SH \"GLOBAL OPTIONS\"\n.TP\n\\fB\\-g1\\fR \\fIstring\\fR\n",
+				".SH \"SUB\\-COMMANDS\"\n.TP\n\\fBadd\\fR\nAdds a remote\n",
+				".SH \"SEE ALSO\"\nprog(1), prog\\-remote\\-add(1)\n",
+			},
+		},
+		{
+			pages[3],
+			[]string{
+				".SH \"SYNOPSIS\"\nprog remote add [[global_options | sub_command_options | parameters]...]\n",
+				".SH \"PARAMETERS\"\n.TP\n\\fB<NAME>\\fR\n",
+				".SH \"SEE ALSO\"\nprog\\-remote(1)\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		out := bytes.NewBuffer([]byte{})
+		if err := test.page.Write(out); err != nil {
+			t.Fatal(err)
+		}
+		for _, contains := range test.contains {
+			if !strings.Contains(out.String(), contains) {
+				t.Errorf("%v does not contain %q\n%v", test.page.Name, contains, out)
+			}
+		}
+	}
+}
+
+func TestSubCommander_ManPages_DisallowGlobalFlagsWithSubCommandOmitsGlobalOptions(t *testing.T) {
+	sc := &SubCommander{
+		CommandName:                       "prog",
+		GlobalFlags:                       clitest.NewStringsFlagSetter("g1"),
+		DisallowGlobalFlagsWithSubCommand: true,
+	}
+	sc.Register(&SubCommandStruct{NameValue: "sub"})
+
+	pages := sc.ManPages("1")
+
+	for _, section := range pages[1].Sections {
+		if section.Name == man.GlobalOptions {
+			t.Fatalf("%v should not have section %v", pages[1].Name, man.GlobalOptions)
+		}
+	}
+}
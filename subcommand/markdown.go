@@ -0,0 +1,165 @@
+package subcommand
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/markdown"
+)
+
+//MarkdownIndexName is the name of the index page returned from MarkdownPages.
+const MarkdownIndexName = "index"
+
+//MarkdownPages returns Markdown reference pages for sc and all of its SubCommands.
+//
+//The first page is an index page named MarkdownIndexName. It has the usage line
+//and global options of sc and links to the page of every SubCommand, including
+//those in Groups.
+//Each SubCommand has a page named by joining the names of the command and
+//SubCommands with "-", e.g. "prog-remote-add".
+//These pages have the usage line, description, options, global options, parameters
+//(or sub-commands for Groups), and links to related pages.
+//
+//Options are listed in tables with their name, type, default, and usage.
+//The output is deterministic for the same sc.
+func (sc *SubCommander) MarkdownPages() []*markdown.Page {
+	name := filepath.Base(sc.CommandName)
+
+	p := &markdown.Page{Name: MarkdownIndexName}
+	p.AddHeading(1, name)
+	p.AddHeading(2, markdown.Synopsis)
+	p.AddCode(sc.getCommandUsage(name))
+	if sc.hasGlobalOptions() {
+		p.AddHeading(2, markdown.GlobalOptions)
+		p.AddFlagTable(sc.globalFlagSet())
+	}
+	if rows := sc.getIndexMarkdownRows(name, nil, &sc.registry); len(rows) > 0 {
+		p.AddHeading(2, markdown.SubCommands)
+		p.AddTable(subCommandsMarkdownHeader, rows)
+	}
+
+	return sc.appendSubCommandMarkdownPages([]*markdown.Page{p}, name, nil, &sc.registry)
+}
+
+var subCommandsMarkdownHeader = []string{"Name", "Aliases", "Synopsis"}
+
+func (sc *SubCommander) getIndexMarkdownRows(name string, parent []SubCommand, r *registry) [][]string {
+	rows := [][]string{}
+	for _, subCommandName := range r.sortedSubCommandNames() {
+		subCommand := r.names[subCommandName]
+		path := append(append([]SubCommand{}, parent...), subCommand)
+
+		rows = append(rows, getSubCommandMarkdownRow(name, path))
+		if group, ok := subCommand.(*Group); ok {
+			rows = append(rows, sc.getIndexMarkdownRows(name, path, &group.registry)...)
+		}
+	}
+	return rows
+}
+
+func (sc *SubCommander) appendSubCommandMarkdownPages(pages []*markdown.Page, name string, parent []SubCommand, r *registry) []*markdown.Page {
+	parentLink := markdown.Link(name, MarkdownIndexName)
+	if len(parent) > 0 {
+		parentLink = markdown.Link(getMarkdownTitle(name, parent), getPageName(name, parent))
+	}
+
+	for _, subCommandName := range r.sortedSubCommandNames() {
+		subCommand := r.names[subCommandName]
+		path := append(append([]SubCommand{}, parent...), subCommand)
+
+		p := &markdown.Page{Name: getPageName(name, path)}
+		p.AddHeading(1, getMarkdownTitle(name, path))
+		p.AddParagraph(subCommand.Synopsis())
+		if len(subCommand.Aliases()) > 0 {
+			p.AddParagraph("Aliases: " + getMarkdownCodes(getSortedAliases(subCommand)...))
+		}
+		p.AddHeading(2, markdown.Synopsis)
+		p.AddCode(name + " " + sc.getSubCommandUsage(path, true))
+		if description := subCommand.Description(); len(description) > 0 {
+			p.AddHeading(2, markdown.Description)
+			p.AddParagraph(description)
+		}
+		if f := cli.NewFlagSet(subCommand.Name(), sc.getPathFlagSetter(path)); cli.CountFlags(f) > 0 {
+			p.AddHeading(2, markdown.Options)
+			p.AddFlagTable(f)
+		}
+		if sc.hasGlobalOptions() && !sc.DisallowGlobalFlagsWithSubCommand {
+			p.AddHeading(2, markdown.GlobalOptions)
+			p.AddFlagTable(sc.globalFlagSet())
+		}
+
+		group, isGroup := subCommand.(*Group)
+		seeAlso := []string{parentLink}
+		if isGroup {
+			rows := [][]string{}
+			for _, childName := range group.sortedSubCommandNames() {
+				childPath := append(append([]SubCommand{}, path...), group.names[childName])
+				rows = append(rows, getSubCommandMarkdownRow(name, childPath))
+				seeAlso = append(seeAlso, markdown.Link(getMarkdownTitle(name, childPath), getPageName(name, childPath)))
+			}
+			if len(rows) > 0 {
+				p.AddHeading(2, markdown.SubCommands)
+				p.AddTable(subCommandsMarkdownHeader, rows)
+			}
+		} else {
+			sc.addParametersMarkdown(p, subCommand)
+		}
+
+		p.AddHeading(2, markdown.SeeAlso)
+		p.AddList(seeAlso...)
+
+		pages = append(pages, p)
+		if isGroup {
+			pages = sc.appendSubCommandMarkdownPages(pages, name, path, &group.registry)
+		}
+	}
+
+	return pages
+}
+
+func (sc *SubCommander) addParametersMarkdown(p *markdown.Page, subCommand SubCommand) {
+	params, usage := subCommand.ParameterUsage()
+	formatted := cli.FormatParameters(params, FormatParameter)
+	if len(formatted) == 0 && len(usage) == 0 {
+		return
+	}
+
+	p.AddHeading(2, markdown.Parameters)
+	if len(formatted) > 0 {
+		p.AddParagraph(markdown.Code(formatted))
+	}
+	p.AddParagraph(usage)
+}
+
+//getSubCommandMarkdownRow returns a table row with a link to the page of the
+//SubCommand at the end of path.
+func getSubCommandMarkdownRow(name string, path []SubCommand) []string {
+	subCommand := path[len(path)-1]
+	return []string{
+		markdown.Link(strings.Join(getPathNames(path), " "), getPageName(name, path)),
+		getMarkdownCodes(getSortedAliases(subCommand)...),
+		subCommand.Synopsis(),
+	}
+}
+
+func getSortedAliases(subCommand SubCommand) []string {
+	aliases := append([]string{}, subCommand.Aliases()...)
+	sort.Strings(aliases)
+	return aliases
+}
+
+func getMarkdownTitle(name string, path []SubCommand) string {
+	return strings.Join(append([]string{name}, getPathNames(path)...), " ")
+}
+
+func getMarkdownCodes(values ...string) string {
+	codes := []string{}
+	for _, value := range values {
+		if len(value) > 0 {
+			codes = append(codes, markdown.Code(value))
+		}
+	}
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,135 @@
+package subcommand
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+)
+
+func TestSubCommander_MarkdownPages(t *testing.T) {
+	remote := &Group{
+		NameValue:        "remote",
+		AliasesValue:     []string{"r"},
+		SynopsisValue:    "Manages remotes",
+		DescriptionValue: "Manages the set of remotes.",
+		FlagSetter:       clitest.NewStringsFlagSetter("r1"),
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:     "add",
+		SynopsisValue: "Adds a remote",
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			ParameterUsageValue: func() ([]*cli.Parameter, string) {
+				return []*cli.Parameter{{Name: "name"}}, "name of the remote"
+			},
+		},
+	})
+
+	sc := &SubCommander{
+		CommandName: "/usr/bin/prog",
+		GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+	}
+	sc.Register(remote)
+	sc.RegisterCompletion("completion", "Prints completion scripts", "")
+
+	pages := sc.MarkdownPages()
+
+	names := []string{}
+	for _, p := range pages {
+		names = append(names, p.FileName())
+	}
+	wantNames := []string{"index.md", "prog-completion.md", "prog-remote.md", "prog-remote-add.md"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("page names = %v WANT %v", names, wantNames)
+	}
+
+	tests := []struct {
+		index    int
+		contains []string
+	}{
+		{
+			0,
+			[]string{
+				"# prog\n\n## Synopsis\n\n```\nprog [global_options...] <sub_command> [[global_options | sub_command_options | parameters]...]\n```\n",
+				"## Global Options\n\n| Name | Type | Default | Usage |\n| --- | --- | --- | --- |\n| `-g1` | string | `g1_default` | g1_usage |\n",
+				"## Sub-commands\n\n| Name | Aliases | Synopsis |\n| --- | --- | --- |\n" +
+					"| [completion](prog-completion.md) |  | Prints completion scripts |\n" +
+					"| [remote](prog-remote.md) | `r` | Manages remotes |\n" +
+					"| [remote add](prog-remote-add.md) |  | Adds a remote |\n",
+			},
+		},
+		{
+			2,
+			[]string{
+				"# prog remote\n\nManages remotes\n\nAliases: `r`\n",
+				"## Description\n\nManages the set of remotes.\n",
+				"## Options\n\n| Name | Type | Default | Usage |\n| --- | --- | --- | --- |\n| `-r1` | string | `r1_default` | r1_usage |\n",
+				"## Global Options\n",
+				"## Sub-commands\n\n| Name | Aliases | Synopsis |\n| --- | --- | --- |\n| [remote add](prog-remote-add.md) |  | Adds a remote |\n",
+				"## See Also\n\n* [prog](index.md)\n* [prog remote add](prog-remote-add.md)\n",
+			},
+		},
+		{
+			3,
+			[]string{
+				"## Synopsis\n\n```\nprog remote add [[global_options | sub_command_options | parameters]...]\n```\n",
+				"## Parameters\n\n`<NAME>`\n\nname of the remote\n",
+				"## See Also\n\n* [prog remote](prog-remote.md)\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p := pages[test.index]
+		out := bytes.NewBuffer([]byte{})
+		if err := p.Write(out); err != nil {
+			t.Fatal(err)
+		}
+		for _, contains := range test.contains {
+			if !strings.Contains(out.String(), contains) {
+				t.Errorf("%v does not contain %q\n%v", p.Name, contains, out)
+			}
+		}
+	}
+}
+
+func TestSubCommander_MarkdownPages_IsDeterministic(t *testing.T) {
+	newPages := func() string {
+		sc := &SubCommander{CommandName: "prog"}
+		for _, name := range []string{"c", "a", "b", "d"} {
+			sc.Register(&SubCommandStruct{NameValue: name, AliasesValue: []string{name + "2", name + "1"}})
+		}
+
+		out := bytes.NewBuffer([]byte{})
+		for _, p := range sc.MarkdownPages() {
+			p.Write(out)
+		}
+		return out.String()
+	}
+
+	first := newPages()
+	for i := 0; i < 10; i++ {
+		if result := newPages(); result != first {
+			t.Fatalf("MarkdownPages() = %v WANT %v", result, first)
+		}
+	}
+}
+
+func TestSubCommander_MarkdownPages_DisallowGlobalFlagsWithSubCommandOmitsGlobalOptions(t *testing.T) {
+	sc := &SubCommander{
+		CommandName:                       "prog",
+		GlobalFlags:                       clitest.NewStringsFlagSetter("g1"),
+		DisallowGlobalFlagsWithSubCommand: true,
+	}
+	sc.Register(&SubCommandStruct{NameValue: "sub"})
+
+	out := bytes.NewBuffer([]byte{})
+	sc.MarkdownPages()[1].Write(out)
+
+	if strings.Contains(out.String(), "## Global Options") {
+		t.Fatalf("%v should not have global options", out)
+	}
+}
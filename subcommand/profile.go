@@ -0,0 +1,166 @@
+package subcommand
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gogolfing/cli"
+)
+
+//Output and configuration values of profiles.
+const (
+	//ProfilesName is the heading of the profiles listed in help output.
+	ProfilesName = "profiles"
+
+	//ProfileSectionName is the first word of the configuration sections of a
+	//profile, e.g. [profile dev] and [profile dev remote add].
+	ProfileSectionName = "profile"
+
+	//ProfileFlagUsage is the usage of the flag of SubCommander.ProfileFlag.
+	ProfileFlagUsage = "name of the configuration profile to apply"
+)
+
+//profileFlagSetter sets the string flag of SubCommander.ProfileFlag.
+type profileFlagSetter string
+
+func (pfs profileFlagSetter) SetFlags(f *flag.FlagSet) {
+	f.String(string(pfs), "", ProfileFlagUsage)
+}
+
+//getProfileSection returns the name of the configuration section of profile
+//for the configuration section named section.
+func getProfileSection(profile, section string) string {
+	return strings.TrimSpace(ProfileSectionName + " " + profile + " " + section)
+}
+
+//splitProfileSection returns the profile and configuration section named by
+//the profile section name. Ok is false if name is not a profile section.
+func (sc *SubCommander) splitProfileSection(name string) (profile, section string, ok bool) {
+	fields := strings.Fields(name)
+	if len(sc.ProfileFlag) == 0 || len(fields) < 2 || fields[0] != ProfileSectionName {
+		return "", "", false
+	}
+	return fields[1], strings.Join(fields[2:], " "), true
+}
+
+//getProfiles returns the sorted names of the profiles that have sections in configs.
+func (sc *SubCommander) getProfiles(configs []*cli.Config) []string {
+	found := map[string]bool{}
+	for _, c := range configs {
+		for _, section := range c.Sections {
+			if profile, _, ok := sc.splitProfileSection(section.Name); ok {
+				found[profile] = true
+			}
+		}
+	}
+
+	profiles := make([]string, 0, len(found))
+	for profile := range found {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+//getDefaultProfile returns the profile selected without the profile flag being
+//present in the arguments, i.e. by the flag's environment variable if present,
+//or else by the value of its key in the unnamed section of s.configs.
+func (sc *SubCommander) getDefaultProfile(s *flagSources) string {
+	if len(sc.ProfileFlag) == 0 {
+		return ""
+	}
+
+	if name, ok := s.envNames[sc.ProfileFlag]; ok {
+		lookupEnv := sc.LookupEnv
+		if lookupEnv == nil {
+			lookupEnv = os.LookupEnv
+		}
+		if value, ok := lookupEnv(name); ok {
+			return value
+		}
+	}
+
+	for _, c := range s.configs {
+		if section := c.Section(""); section != nil {
+			for i := len(section.Values) - 1; i >= 0; i-- {
+				if section.Values[i].Key == sc.ProfileFlag {
+					return section.Values[i].Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+//getActiveProfile returns the value of the profile flag in f if it was present
+//in the arguments and s.profile otherwise.
+func (sc *SubCommander) getActiveProfile(f *flag.FlagSet, s *flagSources) string {
+	profile := s.profile
+	f.Visit(func(fl *flag.Flag) {
+		if len(sc.ProfileFlag) > 0 && fl.Name == sc.ProfileFlag {
+			profile = fl.Value.String()
+		}
+	})
+	return profile
+}
+
+//setProfile sets s.profile to the active profile given f.
+//It returns an UnknownProfileError if the profile has no configuration sections
+//in s.configs.
+func (sc *SubCommander) setProfile(f *flag.FlagSet, s *flagSources) error {
+	s.profile = sc.getActiveProfile(f, s)
+	if len(s.profile) == 0 {
+		return nil
+	}
+	for _, profile := range sc.getProfiles(s.configs) {
+		if profile == s.profile {
+			return nil
+		}
+	}
+	return UnknownProfileError(s.profile)
+}
+
+//profileContextKey is the context.Context key of the active profile.
+type profileContextKey struct{}
+
+//newProfileContext returns a copy of ctx that carries the active profile, so
+//that the help SubCommand may mark it.
+func newProfileContext(ctx context.Context, profile string) context.Context {
+	return context.WithValue(ctx, profileContextKey{}, profile)
+}
+
+//profileFromContext returns the active profile carried by ctx, if any.
+func profileFromContext(ctx context.Context) string {
+	profile, _ := ctx.Value(profileContextKey{}).(string)
+	return profile
+}
+
+//maybePrintProfiles prints the profiles of sc.ConfigFiles, marking active.
+//An error loading sc.ConfigFiles is printed in place of the profiles.
+func (sc *SubCommander) maybePrintProfiles(out io.Writer, active string) {
+	if len(sc.ProfileFlag) == 0 {
+		return
+	}
+	configs, err := sc.ConfigFiles.Load()
+	if err != nil {
+		fmt.Fprintf(out, "\n%s: %v\n", ProfilesName, err)
+		return
+	}
+	profiles := sc.getProfiles(configs)
+	if len(profiles) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "\n%s:\n", ProfilesName)
+	for _, profile := range profiles {
+		if profile == active {
+			profile += " (active)"
+		}
+		fmt.Fprintf(out, "  %s\n", profile)
+	}
+}
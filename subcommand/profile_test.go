@@ -0,0 +1,170 @@
+package subcommand
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/cli/clitest"
+)
+
+func newProfileSubCommander(dir string, env map[string]string) (*SubCommander, *clitest.SimpleFlagSetter, *clitest.SimpleFlagSetter) {
+	gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+	afs := &clitest.SimpleFlagSetter{Suffix: "3"}
+
+	sc := &SubCommander{
+		CommandName: "prog",
+		GlobalFlags: gfs,
+		EnvPrefix:   "prog",
+		LookupEnv:   lookupEnvMap(env),
+		ConfigFiles: getConfigFiles(dir),
+		ProfileFlag: "profile",
+	}
+	sc.Register(newRemoteGroup(afs, nil))
+	return sc, gfs, afs
+}
+
+func TestSubCommander_ExecuteContext_SetsFlagsFromProfile(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"system": "[profile dev]\nint1 = 10\n[profile prod remote add]\nstring3 = prod",
+		"user":   "int1 = 1\nstring1 = user\n[remote add]\nstring3 = user\n[profile dev remote add]\nstring3 = dev\nint3 = 3",
+	})
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		args    string
+		env     map[string]string
+		config  string
+		global  *clitest.SimpleFlagSetter
+		command *clitest.SimpleFlagSetter
+	}{
+		{
+			"remote add",
+			nil,
+			"",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "user"},
+			&clitest.SimpleFlagSetter{Suffix: "3", String: "user"},
+		},
+		{
+			"-profile dev -string1 arg remote add",
+			nil,
+			"",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 10, String: "arg"},
+			&clitest.SimpleFlagSetter{Suffix: "3", Int: 3, String: "dev"},
+		},
+		{
+			"remote add -profile prod",
+			map[string]string{"PROG_PROFILE": "dev"},
+			"",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "user"},
+			&clitest.SimpleFlagSetter{Suffix: "3", String: "prod"},
+		},
+		{
+			"remote add",
+			map[string]string{"PROG_PROFILE": "dev", "PROG_INT1": "20"},
+			"",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 20, String: "user"},
+			&clitest.SimpleFlagSetter{Suffix: "3", Int: 3, String: "dev"},
+		},
+		{
+			"remote add",
+			nil,
+			"profile = prod",
+			&clitest.SimpleFlagSetter{Suffix: "1", Int: 1, String: "user"},
+			&clitest.SimpleFlagSetter{Suffix: "3", String: "prod"},
+		},
+	}
+
+	for i, test := range tests {
+		os.Remove(getConfigFiles(dir).Project)
+		if len(test.config) > 0 {
+			if err := ioutil.WriteFile(getConfigFiles(dir).Project, []byte(test.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sc, gfs, afs := newProfileSubCommander(dir, test.env)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields(test.args),
+		}, i)
+
+		if !reflect.DeepEqual(gfs, test.global) {
+			t.Errorf("%v: global flags = %+v WANT %+v", i, gfs, test.global)
+		}
+		if !reflect.DeepEqual(afs, test.command) {
+			t.Errorf("%v: sub-command flags = %+v WANT %+v", i, afs, test.command)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_UnknownProfile(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "[profile dev]"})
+	defer os.RemoveAll(dir)
+
+	sc, _, _ := newProfileSubCommander(dir, nil)
+	_, _, err := executeContext(sc, nil, strings.Fields("-profile other remote add"), nil)
+
+	if pgae, ok := err.(*ParsingGlobalArgsError); !ok || pgae.Err != UnknownProfileError("other") {
+		t.Fatalf("err = %#v", err)
+	}
+}
+
+func TestSubCommander_ExecuteContext_HelpListsProfiles(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"user":    "[profile prod]\n[profile dev remote]",
+		"project": "[profile test]",
+	})
+	defer os.RemoveAll(dir)
+
+	sc, _, _ := newProfileSubCommander(dir, map[string]string{"PROG_PROFILE": "prod"})
+	_, outErr, _ := executeContext(sc, nil, []string{"-help"}, nil)
+
+	want := "\nprofiles:\n  dev\n  prod (active)\n  test\n"
+	if !strings.Contains(outErr.String(), want) {
+		t.Fatalf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, want)
+	}
+}
+
+func TestSubCommander_ExecuteContext_HelpMarksProfileOfExecution(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "[profile dev]\n[profile prod]"})
+	defer os.RemoveAll(dir)
+
+	sc, _, _ := newProfileSubCommander(dir, nil)
+	sc.RegisterHelp("help", "", "")
+
+	if _, _, err := executeContext(sc, nil, strings.Fields("-profile dev remote add"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	_, outErr, _ := executeContext(sc, nil, []string{"-help"}, nil)
+	if want := "\nprofiles:\n  dev\n  prod\n"; !strings.Contains(outErr.String(), want) {
+		t.Errorf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, want)
+	}
+
+	out, _, err := executeContext(sc, nil, strings.Fields("-profile prod help remote"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\nprofiles:\n  dev\n  prod (active)\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("out =\n%v\nWANT CONTAINS\n%v", out, want)
+	}
+}
+
+func TestSubCommander_ExecuteContext_HelpReportsProfilesLoadError(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{"user": "[profile dev"})
+	defer os.RemoveAll(dir)
+
+	sc, _, _ := newProfileSubCommander(dir, nil)
+	_, outErr, err := executeContext(sc, nil, []string{"-help"}, nil)
+	if err == nil {
+		t.Fatal("err = nil")
+	}
+
+	want := "\n" + ProfilesName + ": " + err.(*ParsingGlobalArgsError).Err.Error() + "\n"
+	if !strings.Contains(outErr.String(), want) {
+		t.Fatalf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, want)
+	}
+}
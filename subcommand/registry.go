@@ -0,0 +1,92 @@
+package subcommand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+)
+
+//registry holds SubCommands by their names and aliases.
+//It is shared by SubCommander and Group so that both levels of a sub-command
+//tree register and look up SubCommands in the same way.
+type registry struct {
+	names   map[string]SubCommand
+	aliases map[string]SubCommand
+}
+
+func (r *registry) register(subCommand SubCommand) {
+	if r.names == nil {
+		r.names = map[string]SubCommand{}
+	}
+	if r.aliases == nil {
+		r.aliases = map[string]SubCommand{}
+	}
+
+	r.names[subCommand.Name()] = subCommand
+	for _, alias := range subCommand.Aliases() {
+		r.aliases[alias] = subCommand
+	}
+}
+
+func (r *registry) getSubCommand(name string) SubCommand {
+	if subCommand, ok := r.names[name]; ok {
+		return subCommand
+	}
+	if subCommand, ok := r.aliases[name]; ok {
+		return subCommand
+	}
+	return nil
+}
+
+//sortedSubCommandNames returns the sorted names of the registered SubCommands
+//that are not hidden.
+func (r *registry) sortedSubCommandNames() []string {
+	names := make([]string, 0, len(r.names))
+	for name, subCommand := range r.names {
+		if !isHidden(subCommand) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *registry) getAvailableSubCommandsUsage() string {
+	names := r.sortedSubCommandNames()
+	if len(names) == 0 {
+		return ""
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(out, "%s:", SubCommandsName)
+
+	allNameAliases := make([]string, 0, len(names))
+	for _, name := range names {
+		subCommand := r.names[name]
+		allNameAliases = append(
+			allNameAliases,
+			getSortedJoinedSubCommandNameAliases(subCommand),
+		)
+	}
+
+	pad := int(math.Max(16, float64(maxLen(allNameAliases)+4)))
+	for i, name := range names {
+		subCommand := r.names[name]
+		nameAliases := allNameAliases[i]
+		fmt.Fprintf(out, "\n  %s%s%s", nameAliases, padRight(pad, nameAliases), subCommand.Synopsis())
+	}
+
+	return out.String()
+}
+
+//hiddenSubCommand is implemented by built-in SubCommands that are not listed
+//in help output or completion scripts.
+type hiddenSubCommand interface {
+	hidden() bool
+}
+
+func isHidden(subCommand SubCommand) bool {
+	h, ok := subCommand.(hiddenSubCommand)
+	return ok && h.hidden()
+}
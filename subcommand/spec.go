@@ -0,0 +1,54 @@
+package subcommand
+
+import (
+	"path/filepath"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/spec"
+)
+
+//Spec returns a machine-readable description of sc and all of its SubCommands,
+//including those in Groups.
+//Hidden built-in SubCommands are not included.
+func (sc *SubCommander) Spec() *spec.Command {
+	return &spec.Command{
+		Name:                              filepath.Base(sc.CommandName),
+		GlobalFlags:                       spec.NewFlags(sc.globalFlagSet()),
+		DisallowGlobalFlagsWithSubCommand: sc.DisallowGlobalFlagsWithSubCommand,
+		SubCommands:                       sc.getSubCommandSpecs(nil, &sc.registry),
+	}
+}
+
+//getSubCommandSpecs returns the specs of the SubCommands in r, whose parent path
+//is path.
+func (sc *SubCommander) getSubCommandSpecs(path []SubCommand, r *registry) []*spec.Command {
+	specs := []*spec.Command{}
+	for _, name := range r.sortedSubCommandNames() {
+		specs = append(specs, sc.getSubCommandSpec(append(path[:len(path):len(path)], r.names[name])))
+	}
+	return specs
+}
+
+//getSubCommandSpec returns the spec of the SubCommand at the end of path. Its
+//flags are defined in the same way as for help output, e.g. with environment
+//variable names.
+func (sc *SubCommander) getSubCommandSpec(path []SubCommand) *spec.Command {
+	subCommand := path[len(path)-1]
+	s := &spec.Command{
+		Name:        subCommand.Name(),
+		Aliases:     getSortedAliases(subCommand),
+		Synopsis:    subCommand.Synopsis(),
+		Description: subCommand.Description(),
+		Flags:       spec.NewFlags(cli.NewFlagSet(subCommand.Name(), sc.getSubCommandFlagSetter(path, nil))),
+	}
+
+	if group, ok := subCommand.(*Group); ok {
+		s.SubCommands = sc.getSubCommandSpecs(path, &group.registry)
+	} else {
+		params, usage := subCommand.ParameterUsage()
+		s.Parameters = spec.NewParameters(params)
+		s.ParameterUsage = usage
+	}
+
+	return s
+}
@@ -0,0 +1,141 @@
+package subcommand
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gogolfing/cli"
+	"github.com/gogolfing/cli/clitest"
+	"github.com/gogolfing/cli/spec"
+)
+
+func TestSubCommander_Spec(t *testing.T) {
+	remote := &Group{
+		NameValue:        "remote",
+		AliasesValue:     []string{"r"},
+		SynopsisValue:    "Manages remotes",
+		DescriptionValue: "Manages the set of remotes.",
+		FlagSetter:       clitest.NewStringsFlagSetter("r1"),
+	}
+	remote.Register(&SubCommandStruct{
+		NameValue:     "add",
+		AliasesValue:  []string{"new", "a"},
+		SynopsisValue: "Adds a remote",
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			ParameterUsageValue: func() ([]*cli.Parameter, string) {
+				return []*cli.Parameter{{Name: "name"}, {Name: "url", Optional: true}}, "remote to add"
+			},
+		},
+	})
+
+	sc := &SubCommander{
+		CommandName: "/usr/bin/prog",
+		GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+	}
+	sc.Register(remote)
+	sc.Register(&SubCommandStruct{
+		NameValue:        "version",
+		SynopsisValue:    "Prints the version",
+		DescriptionValue: "Prints the version of prog.",
+		FlagSetter:       &clitest.SimpleFlagSetter{Suffix: "v"},
+	})
+	sc.RegisterCompletion("completion", "Prints completion scripts", "")
+
+	result := sc.Spec()
+
+	if name := result.SubCommands[0].Name; name != "completion" {
+		t.Fatalf("SubCommands[0].Name = %v WANT %v", name, "completion")
+	}
+
+	want := &spec.Command{
+		Name:        "prog",
+		GlobalFlags: []*spec.Flag{{Name: "g1", Type: "string", Default: "g1_default", Usage: "g1_usage"}},
+		SubCommands: []*spec.Command{
+			result.SubCommands[0],
+			{
+				Name:        "remote",
+				Aliases:     []string{"r"},
+				Synopsis:    "Manages remotes",
+				Description: "Manages the set of remotes.",
+				Flags:       []*spec.Flag{{Name: "r1", Type: "string", Default: "r1_default", Usage: "r1_usage"}},
+				SubCommands: []*spec.Command{
+					{
+						Name:           "add",
+						Aliases:        []string{"a", "new"},
+						Synopsis:       "Adds a remote",
+						Flags:          []*spec.Flag{},
+						Parameters:     []*spec.Parameter{{Name: "name"}, {Name: "url", Optional: true}},
+						ParameterUsage: "remote to add",
+					},
+				},
+			},
+			{
+				Name:        "version",
+				Aliases:     []string{},
+				Synopsis:    "Prints the version",
+				Description: "Prints the version of prog.",
+				Flags: []*spec.Flag{
+					{Name: "boolv", Type: "bool", Usage: "bool_usage"},
+					{Name: "intv", Type: "int", Usage: "int_usage"},
+					{Name: "stringv", Type: "string", Usage: "string_usage"},
+				},
+				Parameters: []*spec.Parameter{},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, want) {
+		resultJSON, _ := json.Marshal(result)
+		wantJSON, _ := json.Marshal(want)
+		t.Fatalf("Spec() = %s WANT %s", resultJSON, wantJSON)
+	}
+}
+
+func TestSubCommander_Spec_WriteJSONIsDeterministic(t *testing.T) {
+	newJSON := func() string {
+		sc := &SubCommander{CommandName: "prog"}
+		for _, name := range []string{"c", "a", "b", "d"} {
+			sc.Register(&SubCommandStruct{NameValue: name, AliasesValue: []string{name + "2", name + "1"}})
+		}
+
+		out := bytes.NewBuffer([]byte{})
+		if err := sc.Spec().WriteJSON(out); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	first := newJSON()
+	for i := 0; i < 10; i++ {
+		if result := newJSON(); result != first {
+			t.Fatalf("WriteJSON() = %v WANT %v", result, first)
+		}
+	}
+}
+
+func TestSubCommander_Spec_AnnotatesGlobalAndSubCommandFlagsAlike(t *testing.T) {
+	remote := &Group{NameValue: "remote", FlagSetter: clitest.NewStringsFlagSetter("r1")}
+	remote.Register(&SubCommandStruct{NameValue: "add", FlagSetter: clitest.NewStringsFlagSetter("a1")})
+
+	sc := &SubCommander{
+		GlobalFlags: clitest.NewStringsFlagSetter("g1"),
+		EnvPrefix:   "PROG",
+	}
+	sc.Register(remote)
+
+	result := sc.Spec()
+	usages := []string{
+		result.GlobalFlags[0].Usage,
+		result.SubCommands[0].Flags[0].Usage,
+		result.SubCommands[0].SubCommands[0].Flags[0].Usage,
+	}
+	want := []string{
+		"g1_usage [$PROG_G1]",
+		"r1_usage [$PROG_REMOTE_R1]",
+		"a1_usage [$PROG_REMOTE_ADD_A1]",
+	}
+	if !reflect.DeepEqual(usages, want) {
+		t.Fatalf("usages = %q WANT %q", usages, want)
+	}
+}
@@ -34,6 +34,13 @@ type SubCommandStruct struct {
 
 	//ExecuteValue is used as the SubCommand's implementation if not nil.
 	ExecuteValue func(context.Context, io.Reader, io.Writer, io.Writer) error
+
+	//StopAtFirstParameterValue is returned from SubCommand's cli.ParameterStopper
+	//implementation.
+	StopAtFirstParameterValue bool
+
+	//CompleteValue is used as the SubCommand's cli.Completer implementation if not nil.
+	CompleteValue func(*cli.Completion) []string
 }
 
 //Name returns scs.NameValue.
@@ -90,3 +97,18 @@ func (scs *SubCommandStruct) Execute(ctx context.Context, in io.Reader, out, out
 	}
 	return nil
 }
+
+//Complete calls and returns the result from scs.CompleteValue(c) if the field
+//is not nil.
+//Otherwise, it returns nil.
+func (scs *SubCommandStruct) Complete(c *cli.Completion) []string {
+	if scs.CompleteValue != nil {
+		return scs.CompleteValue(c)
+	}
+	return nil
+}
+
+//StopAtFirstParameter returns scs.StopAtFirstParameterValue.
+func (scs *SubCommandStruct) StopAtFirstParameter() bool {
+	return scs.StopAtFirstParameterValue
+}
@@ -1,14 +1,11 @@
 package subcommand
 
 import (
-	"bytes"
 	"context"
 	"flag"
 	"fmt"
 	"io"
-	"math"
 	"os"
-	"sort"
 	"strings"
 
 	"github.com/gogolfing/cli"
@@ -42,8 +39,90 @@ type SubCommander struct {
 	//to come before "sub-command" in the argument slice.
 	DisallowGlobalFlagsWithSubCommand bool
 
-	names   map[string]SubCommand
-	aliases map[string]SubCommand
+	//GNUOptions, if true, parses arguments with cli.ParseFlagsGNU and
+	//cli.ParseArgumentsGNU instead of the flag package and
+	//cli.ParseArgumentsInterspersed. Help output then lists the names of each
+	//option together, e.g. "-v, --verbose".
+	GNUOptions bool
+
+	//NegativeNumbers, if true, parses the arguments of SubCommands that are
+	//negative numbers, e.g. -5 or -3.2, as parameters unless a flag of that name
+	//is defined. Help output then documents this with cli.NegativeNumbersUsage.
+	NegativeNumbers bool
+
+	//ResponseFiles, if true, replaces each argument of the form @path by the
+	//arguments in the file at path before parsing. See cli.ExpandResponseFiles.
+	//The words given to the SubCommand registered by RegisterCompletion that
+	//completes values are never expanded, as they may be partial.
+	ResponseFiles bool
+
+	//EnvPrefix, if not empty, allows flags that are not present in the arguments
+	//to take their values from environment variables.
+	//Global flags use cli.EnvName(EnvPrefix, <flag name>), e.g. PROG_TOKEN,
+	//and the flags of SubCommands include the names of the SubCommand and any
+	//Groups before the flag name, e.g. PROG_REMOTE_ADD_TOKEN.
+	//These names are included in the options of help output.
+	EnvPrefix string
+
+	//EnvOpts, if true and EnvPrefix is not empty, prepends default arguments from
+	//environment variables to the arguments. Those in cli.EnvName(EnvPrefix,
+	//cli.EnvOptsName), e.g. PROG_OPTS, precede the global arguments and should only
+	//contain global flags. Those in the variable named by the SubCommand's path,
+	//e.g. PROG_REMOTE_ADD_OPTS, precede the arguments following the SubCommand's name.
+	//Values are split by cli.SplitArgs. The arguments take precedence as they are
+	//parsed after the default arguments. The flags set only by default arguments
+	//have the cli.SourceEnv of their variable in the execution's cli.Provenance.
+	EnvOpts bool
+
+	//LookupEnv is used to look up environment variables if not nil.
+	//Otherwise, os.LookupEnv is used.
+	LookupEnv func(key string) (string, bool)
+
+	//ConfigFiles are configuration files that provide values for flags that are
+	//not present in the arguments or the environment.
+	//Global flags are set from the unnamed section of the files and the flags of
+	//SubCommands from the section named by the SubCommand's Name(), or the Name()s
+	//of each Group and SubCommand in its path separated by spaces,
+	//e.g. [remote add]. See cli.Config for the format.
+	//Unknown sections and keys in any section are parsing errors of every execution.
+	ConfigFiles cli.ConfigFiles
+
+	//OptionsFlag, if not empty, is the name of a boolean global flag,
+	//e.g. "show-options". If it is set, then a table of each global and sub-command
+	//option's value and source is written to out instead of executing the SubCommand.
+	//The table is that of the cli.Provenance that is otherwise available to
+	//SubCommand.Execute through cli.ProvenanceFromContext.
+	OptionsFlag string
+
+	//AliasSection, if not empty, is the name of a configuration section of ConfigFiles,
+	//e.g. "alias", that defines user aliases as keys and their expansions as values,
+	//e.g. "co = checkout -b". A user alias that is the first argument after the
+	//global flags is replaced by its expansion split by cli.SplitArgs, which is
+	//parsed in turn and may begin with global flags or another user alias.
+	//
+	//User aliases may not shadow the names or aliases of registered SubCommands.
+	//They are listed by the SubCommand of RegisterList.
+	AliasSection string
+
+	//ProfileFlag, if not empty, is the name of a string global flag, e.g. "profile",
+	//that selects a named profile of configuration values.
+	//The values of profile NAME are in the sections [profile NAME] for global flags
+	//and [profile NAME <section>] for SubCommands, e.g. [profile dev remote add].
+	//They take precedence over the values of all other sections of ConfigFiles.
+	//
+	//The profile may also be selected by the flag's key in ConfigFiles, or by the
+	//flag's environment variable, e.g. PROG_PROFILE. The environment variable is
+	//only consulted if EnvPrefix is set, as the flag has no variable otherwise.
+	//The available profiles are listed in help output.
+	ProfileFlag string
+
+	//Dirs, if not nil, are the directories passed to SubCommand.Execute through
+	//cli.DirsFromContext. Otherwise, they are created by cli.NewDirs with CommandName
+	//and LookupEnv. Tests may set Dirs so that SubCommands do not use the real
+	//home directory.
+	Dirs *cli.Dirs
+
+	registry
 }
 
 //RegisterHelp registers a help SubCommand that prints out help information about
@@ -106,18 +185,10 @@ func (sc *SubCommander) RegisterList(name, synopsis, description string, aliases
 //Aliases().
 //This will overwrite any previously registered SubCommands with the same Name()s
 //or Aliases().
+//
+//SubCommand may be a *Group in order to build trees of sub-commands.
 func (sc *SubCommander) Register(subCommand SubCommand) {
-	if sc.names == nil {
-		sc.names = map[string]SubCommand{}
-	}
-	if sc.aliases == nil {
-		sc.aliases = map[string]SubCommand{}
-	}
-
-	sc.names[subCommand.Name()] = subCommand
-	for _, alias := range subCommand.Aliases() {
-		sc.aliases[alias] = subCommand
-	}
+	sc.register(subCommand)
 }
 
 //Execute is syntactic sugar for sc.ExecuteContext() with context.Background(), args,
@@ -128,7 +199,8 @@ func (sc *SubCommander) Execute(args []string) error {
 
 //ExecuteContext executes a SubCommand registered with sc with the provided parameters.
 //
-//Ctx is the Context passed unaltered to SubCommand.Execute.
+//Ctx is passed to SubCommand.Execute carrying the cli.Provenance of the global
+//and SubCommand flags. See cli.ProvenanceFromContext.
 //
 //Args should be the program arguments excluding the program name - usually os.Args[1:].
 //
@@ -151,40 +223,45 @@ func (sc *SubCommander) Execute(args []string) error {
 //See the package documentation for more details on error and help output.
 //If this is the error, then execution stops and SubCommand.Execute is never called.
 //
+//When a *Group is named in args, the next argument names one of the Group's SubCommands.
+//An unsupplied or unknown SubCommand of a Group results in a *ParsingSubCommandError
+//wrapping ErrUnsuppliedSubCommand or UnknownSubCommandError respectively.
+//
 //If the error is an *ExecutingSubCommandError then nothing is output by sc.
 func (sc *SubCommander) ExecuteContext(ctx context.Context, args []string, in io.Reader, out, outErr io.Writer) (err error) {
-	var subCommand SubCommand
-	subCommand, err = sc.executeContext(ctx, args, in, out, outErr)
+	var path []SubCommand
+	var profile string
+	path, profile, err = sc.executeContext(ctx, args, in, out, outErr)
 	if err == nil {
 		return
 	}
 
 	if pgfe, ok := err.(*ParsingGlobalArgsError); ok {
 		if pgfe.Err == flag.ErrHelp {
-			sc.printCommandError(outErr, nil, true)
+			sc.printCommandError(outErr, nil, true, profile)
 		} else {
-			sc.printCommandError(outErr, pgfe, true)
+			sc.printCommandError(outErr, pgfe, true, profile)
 		}
 		return
 	}
 
 	if err == ErrUnsuppliedSubCommand {
-		sc.printCommandError(outErr, err, false)
+		sc.printCommandError(outErr, err, false, profile)
 		return
 	}
 
 	if _, ok := err.(UnknownSubCommandError); ok {
-		sc.printCommandError(outErr, err, false)
+		sc.printCommandError(outErr, err, false, profile)
 		return
 	}
 
 	if psce, ok := err.(*ParsingSubCommandError); ok {
 		if psce.Err == flag.ErrHelp {
-			printSubCommandHeaderDescription(outErr, subCommand)
+			printSubCommandHeaderDescription(outErr, path[len(path)-1])
 			fmt.Fprintf(outErr, "%s", "\n\n")
-			sc.printSubCommandError(outErr, nil, true, subCommand)
+			sc.printSubCommandError(outErr, nil, true, path, profile)
 		} else {
-			sc.printSubCommandError(outErr, err, true, subCommand)
+			sc.printSubCommandError(outErr, err, true, path, profile)
 		}
 		return
 	}
@@ -196,51 +273,150 @@ func (sc *SubCommander) ExecuteContext(ctx context.Context, args []string, in io
 	return
 }
 
-func (sc *SubCommander) executeContext(ctx context.Context, args []string, in io.Reader, out, outErr io.Writer) (SubCommand, error) {
-	f := cli.NewFlagSet("", sc.GlobalFlags)
-	if err := f.Parse(args); err != nil {
-		return nil, &ParsingGlobalArgsError{err}
+//executeContext returns the path of SubCommands named in args and the active
+//profile along with any error, so that help output may mark the active profile.
+func (sc *SubCommander) executeContext(ctx context.Context, args []string, in io.Reader, out, outErr io.Writer) ([]SubCommand, string, error) {
+	configs, err := sc.loadConfig()
+	if err != nil {
+		return nil, "", &ParsingGlobalArgsError{err}
+	}
+
+	sources := newFlagSources(configs, cli.NewProvenance())
+	f := cli.NewFlagSet("", nil)
+	sources.setFlags(f, sc.getGlobalFlagSetter(sources.envNames), "")
+	sources.profile = sc.getDefaultProfile(sources)
+	if sc.ResponseFiles && !sc.isCompleteArgs(args) {
+		if args, err = cli.ExpandResponseFiles(args); err != nil {
+			return nil, sources.profile, &ParsingGlobalArgsError{err}
+		}
+	}
+	if args, err = sc.prependEnvOpts(nil, f, args, sources, sc.getFlagsParseOptions()); err != nil {
+		return nil, sources.profile, &ParsingGlobalArgsError{err}
+	}
+	err = sc.parseFlags(f, args)
+	if err == nil {
+		err = sc.expandUserAliases(f, configs)
+	}
+	sources.profile = sc.getActiveProfile(f, sources)
+	if err != nil {
+		return nil, sources.profile, &ParsingGlobalArgsError{err}
+	}
+	if err := sc.setProfile(f, sources); err != nil {
+		return nil, sources.profile, &ParsingGlobalArgsError{err}
 	}
 
 	args = f.Args()
 	if len(args) == 0 {
-		return nil, ErrUnsuppliedSubCommand
+		return nil, sources.profile, ErrUnsuppliedSubCommand
 	}
 	name := args[0]
 	args = args[1:]
 
 	subCommand := sc.getSubCommand(name)
 	if subCommand == nil {
-		return nil, UnknownSubCommandError(name)
+		return nil, sources.profile, UnknownSubCommandError(name)
+	}
+
+	if sc.DisallowGlobalFlagsWithSubCommand {
+		if err := sc.setFlagsFromSources(f, sources); err != nil {
+			return nil, sources.profile, &ParsingGlobalArgsError{err}
+		}
+		globals, profile := f, sources.profile
+		f = cli.NewFlagSet(subCommand.Name(), nil)
+		sources = newFlagSources(configs, sources.provenance)
+		sources.profile, sources.globals = profile, globals
+	}
+
+	path := []SubCommand{subCommand}
+	for _, ok := subCommand.(*Group); ok; _, ok = subCommand.(*Group) {
+		var err error
+		subCommand, args, err = sc.parseGroupArgs(path, f, args, sources)
+		if err != nil {
+			return path, sources.profile, &ParsingSubCommandError{err}
+		}
+		path = append(path, subCommand)
 	}
 
-	return subCommand, sc.executeSubCommand(ctx, f, subCommand, args, in, out, outErr)
+	return path, sources.profile, sc.executeSubCommand(ctx, f, path, args, sources, in, out, outErr)
 }
 
-func (sc *SubCommander) getSubCommand(name string) SubCommand {
-	if subCommand, ok := sc.names[name]; ok {
-		return subCommand
+//parseGroupArgs sets the flags of the Group at the end of path on f and parses
+//args until the name of one of the Group's SubCommands is found.
+//It returns the named SubCommand and the arguments after its name.
+func (sc *SubCommander) parseGroupArgs(path []SubCommand, f *flag.FlagSet, args []string, sources *flagSources) (SubCommand, []string, error) {
+	group := path[len(path)-1].(*Group)
+	sources.setFlags(f, sc.getSubCommandFlagSetter(path, sources.envNames), getConfigSection(path))
+	args, err := sc.prependEnvOpts(path, f, args, sources, sc.getFlagsParseOptions())
+	if err != nil {
+		return nil, nil, err
 	}
-	if subCommand, ok := sc.aliases[name]; ok {
-		return subCommand
+	if err := sc.parseFlags(f, args); err != nil {
+		return nil, nil, err
 	}
-	return nil
+
+	args = f.Args()
+	if len(args) == 0 {
+		return nil, nil, ErrUnsuppliedSubCommand
+	}
+
+	subCommand := group.getSubCommand(args[0])
+	if subCommand == nil {
+		return nil, nil, UnknownSubCommandError(args[0])
+	}
+	return subCommand, args[1:], nil
+}
+
+//getSubCommandPath resolves names as a path of SubCommands through Groups.
+//The returned path contains all SubCommands resolved before an error occurred.
+func (sc *SubCommander) getSubCommandPath(names []string) ([]SubCommand, error) {
+	path := []SubCommand{}
+	r := &sc.registry
+	for _, name := range names {
+		if r == nil {
+			return path, cli.ErrTooManyParameters
+		}
+		subCommand := r.getSubCommand(name)
+		if subCommand == nil {
+			return path, UnknownSubCommandError(name)
+		}
+		path = append(path, subCommand)
+
+		r = nil
+		if group, ok := subCommand.(*Group); ok {
+			r = &group.registry
+		}
+	}
+	return path, nil
 }
 
 func (sc *SubCommander) executeSubCommand(
 	ctx context.Context,
-	gf *flag.FlagSet,
-	subCommand SubCommand,
+	f *flag.FlagSet,
+	path []SubCommand,
 	args []string,
+	sources *flagSources,
 	in io.Reader,
 	out, outErr io.Writer,
 ) (err error) {
-	err = sc.parseSubCommandArgs(subCommand, gf, args)
+	subCommand := path[len(path)-1]
+	err = sc.parseSubCommandArgs(path, f, args, sources)
+	if err == nil {
+		err = sc.readSecrets(f, sources, in)
+	}
 	if err != nil {
 		err = &ParsingSubCommandError{err}
 		return
 	}
 
+	if isOptionsFlagSet(sources.provenance, sc.OptionsFlag) {
+		if err = sources.provenance.WriteTable(out); err != nil {
+			err = &ExecutingSubCommandError{err}
+		}
+		return
+	}
+
+	ctx = cli.NewDirsContext(cli.NewProvenanceContext(ctx, sources.provenance), sc.getDirs())
+	ctx = newProfileContext(ctx, sources.profile)
 	err = subCommand.Execute(ctx, in, out, outErr)
 	if err != nil {
 		err = &ExecutingSubCommandError{err}
@@ -249,24 +425,84 @@ func (sc *SubCommander) executeSubCommand(
 	return
 }
 
-func (sc *SubCommander) parseSubCommandArgs(subCommand SubCommand, gf *flag.FlagSet, args []string) error {
-	f := gf
-	if sc.DisallowGlobalFlagsWithSubCommand {
-		f = cli.NewFlagSet(subCommand.Name(), nil)
-	}
-	if fs, ok := subCommand.(cli.FlagSetter); ok {
-		fs.SetFlags(f)
+//readSecrets calls cli.ReadSecrets with f and any separate FlagSet of the global
+//flags in s so that the standard input is read at most once per execution.
+func (sc *SubCommander) readSecrets(f *flag.FlagSet, s *flagSources, in io.Reader) error {
+	if s.globals != nil {
+		return cli.ReadSecrets(in, s.globals, f)
 	}
+	return cli.ReadSecrets(in, f)
+}
+
+func (sc *SubCommander) parseSubCommandArgs(path []SubCommand, f *flag.FlagSet, args []string, sources *flagSources) error {
+	sources.setFlags(f, sc.getSubCommandFlagSetter(path, sources.envNames), getConfigSection(path))
 
-	params, err := cli.ParseArgumentsInterspersed(f, args)
+	subCommand := path[len(path)-1]
+	args, err := sc.prependEnvOpts(path, f, args, sources, sc.getParseOptions(subCommand))
 	if err != nil {
 		return err
 	}
+	params, passthrough, err := sc.parseArguments(f, args, subCommand)
+	if err != nil {
+		return err
+	}
+	if err := sc.setFlagsFromSources(f, sources); err != nil {
+		return err
+	}
+
+	if err := subCommand.SetParameters(params); err != nil {
+		return err
+	}
+	if ps, ok := subCommand.(cli.PassthroughSetter); ok {
+		return ps.SetPassthrough(passthrough)
+	}
+	return nil
+}
+
+//parseFlags parses the flags in args until the first non-flag argument with the
+//parser selected by sc.GNUOptions. The remaining arguments are in f.Args().
+func (sc *SubCommander) parseFlags(f *flag.FlagSet, args []string) error {
+	if sc.GNUOptions {
+		return cli.ParseFlagsGNU(f, args)
+	}
+	return f.Parse(cli.ExpandOptionalValues(f, args))
+}
 
-	return subCommand.SetParameters(params)
+//getFlagsParseOptions returns the cli.ParseOptions equivalent to parseFlags.
+func (sc *SubCommander) getFlagsParseOptions() cli.ParseOptions {
+	return cli.ParseOptions{GNU: sc.GNUOptions, StopAtFirstParameter: true}
 }
 
-func (sc *SubCommander) printCommandError(out io.Writer, err error, globals bool) {
+//parseArguments parses args for subCommand with the parser selected by
+//sc.GNUOptions and sc.NegativeNumbers and returns the parameters. If subCommand stops at its first
+//parameter, then the parameters are the arguments after the flags, unaltered.
+//The arguments after "--" are returned in passthrough only if subCommand is a
+//cli.PassthroughSetter.
+func (sc *SubCommander) parseArguments(f *flag.FlagSet, args []string, subCommand SubCommand) (params, passthrough []string, err error) {
+	return cli.ParseArguments(f, args, sc.getParseOptions(subCommand))
+}
+
+//getParseOptions returns the cli.ParseOptions used by parseArguments for subCommand.
+func (sc *SubCommander) getParseOptions(subCommand SubCommand) cli.ParseOptions {
+	_, split := subCommand.(cli.PassthroughSetter)
+	return cli.ParseOptions{
+		GNU:                  sc.GNUOptions,
+		StopAtFirstParameter: cli.StopsAtFirstParameter(subCommand),
+		Passthrough:          split,
+		NegativeNumbers:      sc.NegativeNumbers,
+	}
+}
+
+//getFlagSetDefaults returns the defaults of f in the format selected by
+//sc.GNUOptions.
+func (sc *SubCommander) getFlagSetDefaults(f *flag.FlagSet) string {
+	if sc.GNUOptions {
+		return cli.GetGNUFlagSetDefaults(f)
+	}
+	return cli.GetFlagSetDefaults(f)
+}
+
+func (sc *SubCommander) printCommandError(out io.Writer, err error, globals bool, profile string) {
 	if err != nil {
 		fmt.Fprintf(out, "%v\n\n", err)
 	}
@@ -274,40 +510,58 @@ func (sc *SubCommander) printCommandError(out io.Writer, err error, globals bool
 	sc.printCommandUsage(out)
 
 	if globals {
-		sc.maybePrintGlobalOptionsUsage(out)
+		sc.maybePrintGlobalOptionsUsage(out, profile)
 	}
-	sc.maybePrintAvailableSubCommands(out)
+	sc.maybePrintAvailableSubCommands(out, &sc.registry)
 }
 
 func (sc *SubCommander) printCommandUsage(out io.Writer) {
-	fmt.Fprintf(out, "%s %s", Usage, sc.CommandName)
+	fmt.Fprintf(out, "%s %s\n", Usage, sc.getCommandUsage(sc.CommandName))
+}
+
+//getCommandUsage returns name followed by the available command line arguments.
+func (sc *SubCommander) getCommandUsage(name string) string {
+	args := []string{name}
 
 	if sc.hasGlobalOptions() {
-		fmt.Fprintf(out, " %v", FormatArgument(GlobalOptionsName, true, true))
+		args = append(args, FormatArgument(GlobalOptionsName, true, true))
 	}
 
-	fmt.Fprintf(out, " %v", FormatArgument(SubCommandName, false, false))
+	args = append(args, FormatArgument(SubCommandName, false, false))
 
-	sc.maybePrintSubCommandLineUsage(out, nil, true)
+	if subCommandLineUsage := sc.getSubCommandLineUsage(nil, true); len(subCommandLineUsage) > 0 {
+		args = append(args, subCommandLineUsage)
+	}
 
-	fmt.Fprintln(out)
+	return strings.Join(args, " ")
+}
+
+//getDirs returns sc.Dirs if not nil and the Dirs of sc.CommandName otherwise.
+func (sc *SubCommander) getDirs() *cli.Dirs {
+	if sc.Dirs != nil {
+		return sc.Dirs
+	}
+	return cli.NewDirs(sc.CommandName, sc.LookupEnv)
 }
 
-func (sc *SubCommander) maybePrintGlobalOptionsUsage(out io.Writer) {
+func (sc *SubCommander) maybePrintGlobalOptionsUsage(out io.Writer, profile string) {
 	globalFlagsUsage := sc.getGlobalFlagsUsage()
 	if len(globalFlagsUsage) > 0 {
 		fmt.Fprintf(out, "\n%s\n", globalFlagsUsage)
 	}
+	sc.maybePrintProfiles(out, profile)
 }
 
-func (sc *SubCommander) maybePrintAvailableSubCommands(out io.Writer) {
-	availableSubCommandsUsage := sc.getAvailableSubCommandsUsage()
+func (sc *SubCommander) maybePrintAvailableSubCommands(out io.Writer, r *registry) {
+	availableSubCommandsUsage := r.getAvailableSubCommandsUsage()
 	if len(availableSubCommandsUsage) > 0 {
 		fmt.Fprintf(out, "\n%s\n", availableSubCommandsUsage)
 	}
 }
 
-func (sc *SubCommander) printSubCommandError(out io.Writer, err error, globals bool, subCommand SubCommand) {
+func (sc *SubCommander) printSubCommandError(out io.Writer, err error, globals bool, path []SubCommand, profile string) {
+	subCommand := path[len(path)-1]
+
 	if err != nil {
 		if err == flag.ErrHelp {
 			printSubCommandHeaderDescription(out, subCommand)
@@ -317,25 +571,66 @@ func (sc *SubCommander) printSubCommandError(out io.Writer, err error, globals b
 		fmt.Fprintf(out, "%s", "\n\n")
 	}
 
-	fmt.Fprintf(out, "%s %s %s", Usage, "...", subCommand.Name())
+	fmt.Fprintf(out, "%s %s %s\n", Usage, "...", sc.getSubCommandUsage(path, globals))
 
-	sc.maybePrintSubCommandLineUsage(out, subCommand, globals)
+	group, isGroup := subCommand.(*Group)
 
-	fmt.Fprintln(out)
+	hasGlobalOptions, hasSubCommandOptions, _ := sc.getSubCommandUsageStats(path)
+	if globals && hasGlobalOptions && !sc.DisallowGlobalFlagsWithSubCommand {
+		sc.maybePrintGlobalOptionsUsage(out, profile)
+	}
+	if hasSubCommandOptions {
+		sc.maybePrintSubCommandOptionsUsage(out, path)
+	}
+	if isGroup {
+		sc.maybePrintAvailableSubCommands(out, &group.registry)
+	} else {
+		sc.maybePrintParameters(out, subCommand)
+	}
+}
 
-	hasGlobalOptions, hasSubCommandOptions, _ := sc.getSubCommandUsageStats(subCommand)
+//getSubCommandUsage returns the names in path followed by the available command
+//line arguments of the SubCommand at the end of path.
+func (sc *SubCommander) getSubCommandUsage(path []SubCommand, globals bool) string {
+	args := getPathNames(path)
+
+	if _, ok := path[len(path)-1].(*Group); ok {
+		args = append(args, sc.getGroupLineUsage(path, globals))
+	} else if subCommandLineUsage := sc.getSubCommandLineUsage(path, globals); len(subCommandLineUsage) > 0 {
+		args = append(args, subCommandLineUsage)
+	}
+
+	return strings.Join(args, " ")
+}
+
+func (sc *SubCommander) getGroupLineUsage(path []SubCommand, globals bool) string {
+	hasGlobalOptions, hasSubCommandOptions, _ := sc.getSubCommandUsageStats(path)
+
+	options := []string{}
 	if globals && hasGlobalOptions && !sc.DisallowGlobalFlagsWithSubCommand {
-		sc.maybePrintGlobalOptionsUsage(out)
+		options = append(options, GlobalOptionsName)
 	}
 	if hasSubCommandOptions {
-		sc.maybePrintSubCommandOptionsUsage(out, subCommand)
+		options = append(options, SubCommandOptionsName)
+	}
+
+	args := []string{}
+	if optionsUsage := formatArgumentsUsage(options); len(optionsUsage) > 0 {
+		args = append(args, optionsUsage)
+	}
+
+	args = append(args, FormatArgument(SubCommandName, false, false))
+
+	if subCommandLineUsage := sc.getSubCommandLineUsage(nil, globals); len(subCommandLineUsage) > 0 {
+		args = append(args, subCommandLineUsage)
 	}
-	sc.maybePrintParameters(out, subCommand)
+
+	return strings.Join(args, " ")
 }
 
-func (sc *SubCommander) maybePrintSubCommandOptionsUsage(out io.Writer, subCommand SubCommand) {
-	f := cli.NewFlagSet(subCommand.Name(), subCommand)
-	defaults := cli.GetFlagSetDefaults(f)
+func (sc *SubCommander) maybePrintSubCommandOptionsUsage(out io.Writer, path []SubCommand) {
+	f := cli.NewFlagSet(path[len(path)-1].Name(), sc.getPathFlagSetter(path))
+	defaults := sc.getFlagSetDefaults(f)
 	if len(defaults) > 0 {
 		fmt.Fprintf(out, "\n%s:\n%s\n", SubCommandOptionsName, defaults)
 	}
@@ -353,20 +648,17 @@ func (sc *SubCommander) maybePrintParameters(out io.Writer, subCommand SubComman
 		fmt.Fprintf(out, "\n%s", usage)
 		didPrint = true
 	}
+	if sc.NegativeNumbers {
+		fmt.Fprintf(out, "\n%s", cli.NegativeNumbersUsage)
+		didPrint = true
+	}
 	if didPrint {
 		fmt.Fprintln(out)
 	}
 }
 
-func (sc *SubCommander) maybePrintSubCommandLineUsage(out io.Writer, subCommand SubCommand, globals bool) {
-	subCommandLineUsage := sc.getSubCommandLineUsage(subCommand, globals)
-	if len(subCommandLineUsage) > 0 {
-		fmt.Fprintf(out, " %s", subCommandLineUsage)
-	}
-}
-
-func (sc *SubCommander) getSubCommandLineUsage(subCommand SubCommand, globals bool) string {
-	hasGlobalOptions, hasSubCommandOptions, hasParameters := sc.getSubCommandUsageStats(subCommand)
+func (sc *SubCommander) getSubCommandLineUsage(path []SubCommand, globals bool) string {
+	hasGlobalOptions, hasSubCommandOptions, hasParameters := sc.getSubCommandUsageStats(path)
 
 	args := []string{}
 	if globals && hasGlobalOptions && !sc.DisallowGlobalFlagsWithSubCommand {
@@ -379,6 +671,12 @@ func (sc *SubCommander) getSubCommandLineUsage(subCommand SubCommand, globals bo
 		args = append(args, ParametersName)
 	}
 
+	return formatArgumentsUsage(args)
+}
+
+//formatArgumentsUsage formats args as a repeatable, optional choice between
+//each of args.
+func formatArgumentsUsage(args []string) string {
 	if len(args) == 0 {
 		return ""
 	}
@@ -389,16 +687,21 @@ func (sc *SubCommander) getSubCommandLineUsage(subCommand SubCommand, globals bo
 	return FormatArgument(FormatArgument(joined, true, false), true, true)
 }
 
-func (sc *SubCommander) getSubCommandUsageStats(subCommand SubCommand) (hasGlobalOptions, hasSubCommandOptions, hasParameters bool) {
+//getSubCommandUsageStats returns statistics about the SubCommand at the end of
+//path. If path is empty, then the SubCommand is unknown and the sub-command
+//stats are true.
+func (sc *SubCommander) getSubCommandUsageStats(path []SubCommand) (hasGlobalOptions, hasSubCommandOptions, hasParameters bool) {
 	hasGlobalOptions = sc.hasGlobalOptions()
 	hasSubCommandOptions = true
 	hasParameters = true
 
-	if subCommand != nil {
+	if len(path) > 0 {
+		subCommand := path[len(path)-1]
+
 		params, _ := subCommand.ParameterUsage()
 		hasParameters = len(params) > 0
 
-		hasSubCommandOptions = cli.CountFlags(cli.NewFlagSet(subCommand.Name(), subCommand)) > 0
+		hasSubCommandOptions = cli.CountFlags(cli.NewFlagSet(subCommand.Name(), sc.getPathFlagSetter(path))) > 0
 	}
 
 	return
@@ -409,11 +712,11 @@ func (sc *SubCommander) hasGlobalOptions() bool {
 }
 
 func (sc *SubCommander) globalFlagSet() *flag.FlagSet {
-	return cli.NewFlagSet("", sc.GlobalFlags)
+	return cli.NewFlagSet("", sc.getGlobalFlagSetter(nil))
 }
 
 func (sc *SubCommander) getGlobalFlagsUsage() string {
-	defaults := cli.GetFlagSetDefaults(sc.globalFlagSet())
+	defaults := sc.getFlagSetDefaults(sc.globalFlagSet())
 	if len(defaults) == 0 {
 		return ""
 	}
@@ -421,35 +724,6 @@ func (sc *SubCommander) getGlobalFlagsUsage() string {
 	return fmt.Sprintf("%s:\n%s", GlobalOptionsName, defaults)
 }
 
-func (sc *SubCommander) getAvailableSubCommandsUsage() string {
-	if len(sc.names) == 0 {
-		return ""
-	}
-
-	out := bytes.NewBuffer([]byte{})
-	fmt.Fprintf(out, "%s:", SubCommandsName)
-
-	names := sc.sortedSubCommandNames()
-
-	allNameAliases := make([]string, 0, len(names))
-	for _, name := range names {
-		subCommand := sc.names[name]
-		allNameAliases = append(
-			allNameAliases,
-			getSortedJoinedSubCommandNameAliases(subCommand),
-		)
-	}
-
-	pad := int(math.Max(16, float64(maxLen(allNameAliases)+4)))
-	for i, name := range names {
-		sc := sc.names[name]
-		nameAliases := allNameAliases[i]
-		fmt.Fprintf(out, "\n  %s%s%s", nameAliases, padRight(pad, nameAliases), sc.Synopsis())
-	}
-
-	return out.String()
-}
-
 func printSubCommandHeaderDescription(out io.Writer, subCommand SubCommand) {
 	fmt.Fprintf(
 		out,
@@ -465,15 +739,6 @@ func getSortedJoinedSubCommandNameAliases(subCommand SubCommand) string {
 	return cli.GetJoinedNameSortedAliases(subCommand.Name(), subCommand.Aliases())
 }
 
-func (sc *SubCommander) sortedSubCommandNames() []string {
-	names := make([]string, 0, len(sc.names))
-	for name := range sc.names {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-	return names
-}
-
 func maxLen(values []string) int {
 	max := 0
 	for _, value := range values {
@@ -496,7 +761,7 @@ func padRight(count int, value string) string {
 type helpSubCommand struct {
 	sc *SubCommander
 
-	helpSubCommandName string
+	helpSubCommandPath []string
 
 	*SubCommandStruct
 }
@@ -510,10 +775,9 @@ func (h *helpSubCommand) ParameterUsage() ([]*cli.Parameter, string) {
 	return params, usage
 }
 
+//SetParameters accepts a single sub-command name or a full path of names through
+//Groups, e.g. ["remote" "add"].
 func (h *helpSubCommand) SetParameters(params []string) error {
-	if len(params) > 1 {
-		return cli.ErrTooManyParameters
-	}
 	if len(params) == 0 {
 		return &cli.RequiredParameterNotSetError{
 			Name: SubCommandName,
@@ -526,27 +790,50 @@ func (h *helpSubCommand) SetParameters(params []string) error {
 			),
 		}
 	}
+	if !h.sc.isGroupPath(params[:len(params)-1]) {
+		return cli.ErrTooManyParameters
+	}
 
-	h.helpSubCommandName = params[0]
+	h.helpSubCommandPath = params
 	return nil
 }
 
-func (h *helpSubCommand) Execute(_ context.Context, _ io.Reader, out, outErr io.Writer) error {
-	subCommand := h.sc.getSubCommand(h.helpSubCommandName)
-	if subCommand == nil {
-		err := UnknownSubCommandError(h.helpSubCommandName)
-		h.sc.printCommandError(outErr, err, false)
+func (h *helpSubCommand) Execute(ctx context.Context, _ io.Reader, out, outErr io.Writer) error {
+	profile := profileFromContext(ctx)
+	path, err := h.sc.getSubCommandPath(h.helpSubCommandPath)
+	if err != nil {
+		if len(path) == 0 {
+			h.sc.printCommandError(outErr, err, false, profile)
+		} else {
+			h.sc.printSubCommandError(outErr, err, false, path, profile)
+		}
 		return err
 	}
 
+	subCommand := path[len(path)-1]
 	_, helpOk := subCommand.(*helpSubCommand)
 	_, listOk := subCommand.(*listSubCommand)
 
-	h.sc.printSubCommandError(out, flag.ErrHelp, !helpOk && !listOk, subCommand)
+	h.sc.printSubCommandError(out, flag.ErrHelp, !helpOk && !listOk, path, profile)
 
 	return nil
 }
 
+//isGroupPath returns whether or not each name in names resolves to a *Group
+//starting from sc.
+func (sc *SubCommander) isGroupPath(names []string) bool {
+	path, err := sc.getSubCommandPath(names)
+	if err != nil {
+		return false
+	}
+	for _, subCommand := range path {
+		if _, ok := subCommand.(*Group); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 type listSubCommand struct {
 	sc *SubCommander
 
@@ -566,5 +853,13 @@ func (l *listSubCommand) SetParameters(params []string) error {
 
 func (l *listSubCommand) Execute(_ context.Context, _ io.Reader, out, _ io.Writer) error {
 	fmt.Fprintf(out, "%s\n", l.sc.getAvailableSubCommandsUsage())
+
+	configs, err := l.sc.loadConfig()
+	if err != nil {
+		return err
+	}
+	if aliasesUsage := l.sc.getUserAliasesUsage(configs); len(aliasesUsage) > 0 {
+		fmt.Fprintf(out, "\n%s\n", aliasesUsage)
+	}
 	return nil
 }
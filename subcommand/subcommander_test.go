@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -223,7 +224,7 @@ func TestSubCommander_ExecuteContext_ParsingSubCommandError_FlagErrHelp(t *testi
 }
 
 func TestSubCommander_ExecuteContext_ParsingSubCommandError_SettingParametersError(t *testing.T) {
-	err := fmt.Errorf(t.Name())
+	err := errors.New(t.Name())
 
 	sct := &SubCommanderTest{
 		SubCommands: []SubCommand{
@@ -402,6 +403,37 @@ func TestSubCommander_ExecuteContext_DefaultSubCommandOptionsDontOverrideGlobals
 	}
 }
 
+func TestSubCommander_ExecuteContext_ReadsSecretsFromIn(t *testing.T) {
+	for _, disallow := range []bool{false, true} {
+		password, token := "", ""
+		sc := &SubCommander{
+			GlobalFlags: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				cli.SecretVar(f, &password, "password", "")
+			}),
+			DisallowGlobalFlagsWithSubCommand: disallow,
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue: "sub",
+			FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				cli.SecretVar(f, &token, "token", "")
+			}),
+		})
+
+		_, _, err := executeContext(sc, nil, strings.Fields("-password - sub -token plain"), strings.NewReader("stdin secret\n"))
+		if err != nil {
+			t.Fatalf("%v: %v", disallow, err)
+		}
+		if password != "stdin secret" || token != "plain" {
+			t.Errorf("%v: password, token = %q, %q", disallow, password, token)
+		}
+
+		_, _, err = executeContext(sc, nil, strings.Fields("-password - sub -token -"), strings.NewReader("stdin secret\n"))
+		if perr, ok := err.(*ParsingSubCommandError); !ok || perr.Err != cli.ErrSecretStdinReused {
+			t.Errorf("%v: err = %v WANT %v", disallow, err, cli.ErrSecretStdinReused)
+		}
+	}
+}
+
 func TestSubCommander_ExecuteContext_WorksCorrectlyWithGlobalOptionsAfterSubCommandAndCorrectOutputsAndCorrectErrorHappyPath(t *testing.T) {
 	gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
 	sfs := &clitest.SimpleFlagSetter{Suffix: "2"}
@@ -448,7 +480,7 @@ func TestSubCommander_ExecuteContext_WorksCorrectlyWithGlobalOptionsAfterSubComm
 				},
 				ExecuteValue: func(actualCtx context.Context, in io.Reader, out, outErr io.Writer) error {
 					executeCalled = true
-					if actualCtx != ctx {
+					if actualCtx.Value(0) != 1 || cli.ProvenanceFromContext(actualCtx) == nil {
 						t.Error("did not receive correct context in execute method")
 					}
 					if actualIn != in {
@@ -721,3 +753,277 @@ func executeContext(sc *SubCommander, ctx context.Context, args []string, in io.
 
 	return out, outErr, err
 }
+
+func TestSubCommander_ExecuteContext_ExecuteReceivesDirs(t *testing.T) {
+	var dirs *cli.Dirs
+	sc := &SubCommander{
+		CommandName: "prog",
+		LookupEnv:   lookupEnvMap(map[string]string{"HOME": "/home/user"}),
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue: "sub",
+		ExecuteValue: func(ctx context.Context, _ io.Reader, _, _ io.Writer) error {
+			dirs = cli.DirsFromContext(ctx)
+			return nil
+		},
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"sub"},
+	})
+	if want := cli.NewDirs("prog", sc.LookupEnv); !reflect.DeepEqual(dirs, want) {
+		t.Fatalf("dirs = %+v WANT %+v", dirs, want)
+	}
+
+	sc.Dirs = &cli.Dirs{Config: "config"}
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"sub"},
+	})
+	if dirs != sc.Dirs {
+		t.Fatalf("dirs = %+v WANT %+v", dirs, sc.Dirs)
+	}
+}
+
+func TestSubCommander_ExecuteContext_ExpandsResponseFiles(t *testing.T) {
+	dir := tempConfigDir(t, map[string]string{
+		"global": "-int1 1 sub",
+		"sub":    "-string2\x00a b\x00@param\x00",
+	})
+	defer os.RemoveAll(dir)
+
+	gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+	sfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+	var params []string
+	sc := &SubCommander{
+		GlobalFlags:   gfs,
+		ResponseFiles: true,
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue:  "sub",
+		FlagSetter: sfs,
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			SetParametersValue: func(p []string) error {
+				params = p
+				return nil
+			},
+		},
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         []string{"@" + filepath.Join(dir, "global"), "@" + filepath.Join(dir, "sub")},
+	})
+
+	if !reflect.DeepEqual(gfs, &clitest.SimpleFlagSetter{Suffix: "1", Int: 1}) {
+		t.Errorf("global flags = %+v", gfs)
+	}
+	if !reflect.DeepEqual(sfs, &clitest.SimpleFlagSetter{Suffix: "2", String: "a b"}) {
+		t.Errorf("sub-command flags = %+v", sfs)
+	}
+	if !reflect.DeepEqual(params, []string{"@param"}) {
+		t.Errorf("params = %q", params)
+	}
+
+	_, _, err := executeContext(sc, nil, []string{"@" + filepath.Join(dir, "missing")}, nil)
+	if pgae, ok := err.(*ParsingGlobalArgsError); !ok {
+		t.Errorf("err = %#v", err)
+	} else if _, ok := pgae.Err.(*cli.ResponseFileError); !ok {
+		t.Errorf("err = %#v", pgae.Err)
+	}
+}
+
+func TestSubCommander_ExecuteContext_GNUOptions(t *testing.T) {
+	var verbose bool
+	var count int
+	var params []string
+	sc := &SubCommander{
+		GlobalFlags: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+			f.BoolVar(&verbose, "v", false, "verbose output")
+			f.BoolVar(&verbose, "verbose", false, "verbose output")
+		}),
+		GNUOptions: true,
+	}
+	sc.Register(&SubCommandStruct{
+		NameValue: "sub",
+		FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+			f.IntVar(&count, "n", 0, "count")
+			f.IntVar(&count, "count", 0, "count")
+		}),
+		ParameterSetter: &clitest.ParameterSetterStruct{
+			SetParametersValue: func(p []string) error {
+				params = p
+				return nil
+			},
+		},
+	})
+
+	testSubCommanderTest(t, &SubCommanderTest{
+		SubCommander: sc,
+		Args:         strings.Fields("--verb sub one -n2 -- -v"),
+	})
+	if !verbose || count != 2 || !reflect.DeepEqual(params, []string{"one", "-v"}) {
+		t.Fatalf("verbose, count, params = %v, %v, %q", verbose, count, params)
+	}
+
+	_, outErr, _ := executeContext(sc, nil, strings.Fields("sub -h"), nil)
+	want := strings.Join([]string{
+		"  -n, --count int",
+		"    \tcount",
+	}, "\n")
+	if !strings.Contains(outErr.String(), want) {
+		t.Fatalf("outErr =\n%v\nWANT CONTAINS\n%v", outErr, want)
+	}
+}
+
+func TestSubCommander_ExecuteContext_StopAtFirstParameter(t *testing.T) {
+	for _, gnu := range []bool{false, true} {
+		gfs := &clitest.SimpleFlagSetter{Suffix: "1"}
+		sfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+		var params []string
+		sc := &SubCommander{
+			GlobalFlags: gfs,
+			GNUOptions:  gnu,
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue:  "exec",
+			FlagSetter: sfs,
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				SetParametersValue: func(p []string) error {
+					params = p
+					return nil
+				},
+			},
+			StopAtFirstParameterValue: true,
+		})
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields(map[bool]string{false: "exec -bool1 -bool2", true: "exec --bool1 --bool2"}[gnu] + " cmd -bool2 -int1 1 -- arg"),
+		}, gnu)
+
+		if gfs.Int != 0 || !gfs.Bool || !sfs.Bool {
+			t.Errorf("%v: flags = %+v %+v", gnu, gfs, sfs)
+		}
+		if want := strings.Fields("cmd -bool2 -int1 1 -- arg"); !reflect.DeepEqual(params, want) {
+			t.Errorf("%v: params = %q WANT %q", gnu, params, want)
+		}
+	}
+}
+
+type passthroughSubCommand struct {
+	*SubCommandStruct
+	passthrough []string
+}
+
+func (ps *passthroughSubCommand) SetPassthrough(args []string) error {
+	ps.passthrough = args
+	return nil
+}
+
+func TestSubCommander_ExecuteContext_SetsPassthrough(t *testing.T) {
+	for _, gnu := range []bool{false, true} {
+		var params []string
+		ps := &passthroughSubCommand{
+			SubCommandStruct: &SubCommandStruct{
+				NameValue:  "run",
+				FlagSetter: &clitest.SimpleFlagSetter{Suffix: "2"},
+				ParameterSetter: &clitest.ParameterSetterStruct{
+					SetParametersValue: func(p []string) error {
+						params = p
+						return nil
+					},
+				},
+			},
+		}
+		sc := &SubCommander{GNUOptions: gnu}
+		sc.Register(ps)
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("run a -- b -int2 1"),
+		}, gnu)
+
+		if want := []string{"a"}; !reflect.DeepEqual(params, want) {
+			t.Errorf("%v: params = %q WANT %q", gnu, params, want)
+		}
+		if want := []string{"b", "-int2", "1"}; !reflect.DeepEqual(ps.passthrough, want) {
+			t.Errorf("%v: passthrough = %q WANT %q", gnu, ps.passthrough, want)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_NegativeNumbers(t *testing.T) {
+	for _, gnu := range []bool{false, true} {
+		sfs := &clitest.SimpleFlagSetter{Suffix: "2"}
+		var params []string
+		sc := &SubCommander{
+			GNUOptions:      gnu,
+			NegativeNumbers: true,
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue:  "move",
+			FlagSetter: sfs,
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				SetParametersValue: func(p []string) error {
+					params = p
+					return nil
+				},
+			},
+		})
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields(map[bool]string{false: "move -5 -bool2", true: "move -5 --bool2"}[gnu] + " -0.25"),
+		}, gnu)
+
+		if !sfs.Bool || !reflect.DeepEqual(params, []string{"-5", "-0.25"}) {
+			t.Errorf("%v: flags, params = %+v, %q", gnu, sfs, params)
+		}
+
+		_, outErr, _ := executeContext(sc, nil, strings.Fields("move -h"), nil)
+		if !strings.Contains(outErr.String(), "\n"+cli.NegativeNumbersUsage+"\n") {
+			t.Errorf("%v: outErr =\n%v\nWANT CONTAINS\n%v", gnu, outErr, cli.NegativeNumbersUsage)
+		}
+	}
+}
+
+func TestSubCommander_ExecuteContext_OptionalValueFlags(t *testing.T) {
+	for _, gnu := range []bool{false, true} {
+		var color, format string
+		var params []string
+		sc := &SubCommander{
+			GlobalFlags: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				cli.OptionalStringVar(f, &color, "color", "always", "colorize the output `WHEN`")
+			}),
+			GNUOptions: gnu,
+		}
+		sc.Register(&SubCommandStruct{
+			NameValue: "show",
+			FlagSetter: clitest.FlagSetterFunc(func(f *flag.FlagSet) {
+				cli.OptionalStringVar(f, &format, "format", "short", "")
+			}),
+			ParameterSetter: &clitest.ParameterSetterStruct{
+				SetParametersValue: func(p []string) error {
+					params = p
+					return nil
+				},
+			},
+		})
+
+		testSubCommanderTest(t, &SubCommanderTest{
+			SubCommander: sc,
+			Args:         strings.Fields("--color show --format name"),
+		}, gnu)
+
+		if color != "always" || format != "short" || !reflect.DeepEqual(params, []string{"name"}) {
+			t.Errorf("%v: color, format, params = %q, %q, %q", gnu, color, format, params)
+		}
+
+		_, outErr, _ := executeContext(sc, nil, strings.Fields("-h"), nil)
+		if want := map[bool]string{false: "  -color[=WHEN]\n", true: "  --color[=WHEN]\n"}[gnu]; !strings.Contains(outErr.String(), want) {
+			t.Errorf("%v: outErr =\n%v\nWANT CONTAINS\n%v", gnu, outErr, want)
+		}
+	}
+}